@@ -0,0 +1,150 @@
+package hapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// BindError reports a filter value that could not be converted to the type of
+// the struct field it is bound to.
+type BindError struct {
+	StructField string         // The name of the destination struct field
+	Field       string         // The query field name
+	Operator    FilterOperator // The filter operator
+	Value       Value          // The value that failed to convert
+	Err         error          // The underlying conversion error
+}
+
+func (e *BindError) Error() string {
+	return fmt.Sprintf("cannot bind %s[%s]=%q to field %s: %v", e.Field, e.Operator, e.Value, e.StructField, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// Bind fills the struct pointed to by dst with the filter values of a Result.
+// Each struct field tagged `hapi:"field,operator"` receives the values of the
+// first filter matching that field and operator; the operator defaults to eq.
+//
+//	type UserQuery struct {
+//		Name     *string  `hapi:"name"`
+//		MinAge   *int     `hapi:"age,ge"`
+//		Statuses []string `hapi:"status,in"`
+//	}
+//
+// Supported field types are those of As and slices of those. Slice fields
+// receive every value of the filter, other fields its first value. Fields
+// without a matching filter are left untouched, so pointer fields stay nil when
+// the filter is absent. Fields promoted from a nil embedded struct pointer
+// allocate it when bound, unless the embedded struct is unexported.
+//
+// Relative times are evaluated against Result.Now, the time read from
+// Options.Clock, or the current time when no clock was set.
+//
+// Conversion failures do not stop binding: every failing field is reported as a
+// *BindError and the errors are returned joined together.
+func Bind(r Result, dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("hapi: bind destination must be a non-nil pointer to a struct, got %T", dst)
+	}
+	rv = rv.Elem()
+
+	now := r.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	var errs []error
+	for _, sf := range reflect.VisibleFields(rv.Type()) {
+		tag, tagged := sf.Tag.Lookup("hapi")
+		if !tagged || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		field, tagOptions := parseTag(tag)
+		if field == "" {
+			field = sf.Name
+		}
+
+		operator := FilterOperatorEqual
+		if len(tagOptions) > 0 {
+			operator = FilterOperator(tagOptions[0])
+			if err := operator.Valid(); err != nil {
+				return fmt.Errorf("hapi: field %s of %s: %w", sf.Name, rv.Type(), err)
+			}
+		}
+
+		filter, ok := findFilter(r.Filters, field, operator)
+		if !ok {
+			continue
+		}
+
+		fv, ok := fieldByIndex(rv, sf.Index)
+		if !ok {
+			continue
+		}
+
+		if value, err := bindValues(fv, filter.Values, now); err != nil {
+			errs = append(errs, &BindError{
+				StructField: sf.Name,
+				Field:       field,
+				Operator:    operator,
+				Value:       value,
+				Err:         err,
+			})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// findFilter returns the first filter matching field and operator.
+func findFilter(filters Filters, field string, operator FilterOperator) (Filter, bool) {
+	for _, filter := range filters {
+		if filter.Field == field && filter.Operator == operator {
+			return filter, true
+		}
+	}
+	return Filter{}, false
+}
+
+// fieldByIndex returns the field of v at index, allocating the nil embedded
+// struct pointers it is promoted through. It reports false when such a pointer
+// cannot be allocated, i.e. when the embedded struct is unexported.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// bindValues stores values into the field fv, evaluating relative times
+// against now. On failure it returns the value that could not be converted.
+func bindValues(fv reflect.Value, values Values, now time.Time) (Value, error) {
+	if fv.Kind() == reflect.Slice {
+		slice := reflect.MakeSlice(fv.Type(), len(values), len(values))
+		for i, v := range values {
+			if err := convert(slice.Index(i), v, now); err != nil {
+				return v, err
+			}
+		}
+		fv.Set(slice)
+		return "", nil
+	}
+
+	v := values.First()
+	return v, convert(fv, v, now)
+}
@@ -0,0 +1,169 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type bindQuery struct {
+	Name      *string    `hapi:"name"`
+	MinAge    *int       `hapi:"age,ge"`
+	MaxAge    *int       `hapi:"age,le"`
+	Statuses  []string   `hapi:"status,in"`
+	IDs       []uint     `hapi:"id,in"`
+	Score     float64    `hapi:"score,gt"`
+	Verified  *bool      `hapi:"verified"`
+	CreatedAt *time.Time `hapi:"created,ge"`
+	Limit     int        `hapi:"limit"`
+	Ignored   string     `hapi:"-"`
+	Untagged  string
+}
+
+func TestBind(t *testing.T) {
+	r, err := Parse("http://x/users?name=John&age[ge]=18&status[in]=active,pending&id[in]=1,2&score[gt]=4.5&verified=true&created[ge]=2024-01-02T15:04:05Z", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var q bindQuery
+	if err := Bind(r, &q); err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Name == nil || *q.Name != "John" {
+		t.Errorf("Name = %v, want John", q.Name)
+	}
+	if q.MinAge == nil || *q.MinAge != 18 {
+		t.Errorf("MinAge = %v, want 18", q.MinAge)
+	}
+	if q.MaxAge != nil {
+		t.Errorf("MaxAge = %v, want nil for absent filter", *q.MaxAge)
+	}
+	if !reflect.DeepEqual(q.Statuses, []string{"active", "pending"}) {
+		t.Errorf("Statuses = %v, want [active pending]", q.Statuses)
+	}
+	if !reflect.DeepEqual(q.IDs, []uint{1, 2}) {
+		t.Errorf("IDs = %v, want [1 2]", q.IDs)
+	}
+	if q.Score != 4.5 {
+		t.Errorf("Score = %v, want 4.5", q.Score)
+	}
+	if q.Verified == nil || !*q.Verified {
+		t.Errorf("Verified = %v, want true", q.Verified)
+	}
+	if want := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC); q.CreatedAt == nil || !q.CreatedAt.Equal(want) {
+		t.Errorf("CreatedAt = %v, want %v", q.CreatedAt, want)
+	}
+	if q.Limit != 0 {
+		t.Errorf("Limit = %d, want untouched 0", q.Limit)
+	}
+}
+
+// A pointer to zero must be distinguishable from an absent filter.
+func TestBindZeroValue(t *testing.T) {
+	r, _ := Parse("http://x/users?age[ge]=0", Options{})
+
+	var q bindQuery
+	if err := Bind(r, &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.MinAge == nil || *q.MinAge != 0 {
+		t.Errorf("MinAge = %v, want pointer to 0", q.MinAge)
+	}
+}
+
+func TestBindConversionErrors(t *testing.T) {
+	r, _ := Parse("http://x/users?age[ge]=abc&id[in]=1,x&name=ok&verified=maybe", Options{})
+
+	var q bindQuery
+	err := Bind(r, &q)
+	if err == nil {
+		t.Fatal("Bind() expected error, got nil")
+	}
+
+	var bindErrs []*BindError
+	for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+		var bindErr *BindError
+		if !errors.As(e, &bindErr) {
+			t.Fatalf("error %v is not a *BindError", e)
+		}
+		bindErrs = append(bindErrs, bindErr)
+	}
+
+	if len(bindErrs) != 3 {
+		t.Fatalf("got %d errors, want 3: %v", len(bindErrs), err)
+	}
+	if e := bindErrs[0]; e.StructField != "MinAge" || e.Field != "age" || e.Operator != FilterOperatorGreaterOrEqual || e.Value != "abc" {
+		t.Errorf("first error = %+v, want MinAge age ge abc", e)
+	}
+	if !errors.Is(bindErrs[0], strconv.ErrSyntax) {
+		t.Errorf("first error does not unwrap to strconv.ErrSyntax: %v", bindErrs[0])
+	}
+	if e := bindErrs[1]; e.StructField != "IDs" || e.Value != "x" {
+		t.Errorf("second error = %+v, want IDs x", e)
+	}
+	if q.IDs != nil {
+		t.Errorf("IDs = %v, want untouched on failure", q.IDs)
+	}
+
+	// Fields that converted successfully are still bound.
+	if q.Name == nil || *q.Name != "ok" {
+		t.Errorf("Name = %v, want ok", q.Name)
+	}
+}
+
+func TestBindInvalidDestination(t *testing.T) {
+	var q bindQuery
+	for _, dst := range []any{nil, q, (*bindQuery)(nil), new(int)} {
+		if err := Bind(Result{}, dst); err == nil {
+			t.Errorf("Bind(%T) expected error, got nil", dst)
+		}
+	}
+
+	type badOperator struct {
+		Age int `hapi:"age,gte"`
+	}
+	if err := Bind(Result{}, &badOperator{}); err == nil {
+		t.Error("Bind() expected error for invalid operator tag, got nil")
+	}
+}
+
+type BindBase struct {
+	Name *string `hapi:"name"`
+}
+
+type bindHidden struct {
+	Age *int `hapi:"age"`
+}
+
+// Fields promoted through nil embedded pointers do not panic.
+func TestBindEmbeddedPointer(t *testing.T) {
+	var q struct {
+		*BindBase
+		*bindHidden
+	}
+
+	r, err := Parse("http://x/users?name=John&age=30", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Bind(r, &q); err != nil {
+		t.Fatal(err)
+	}
+	if q.BindBase == nil || q.Name == nil || *q.Name != "John" {
+		t.Errorf("Bind() Name = %v, want John", q.BindBase)
+	}
+	if q.bindHidden != nil {
+		t.Errorf("Bind() allocated the unexported embedded struct: %+v", q.bindHidden)
+	}
+
+	// The embedded struct is not allocated without a matching filter.
+	q.BindBase = nil
+	r, _ = Parse("http://x/users", Options{})
+	if err := Bind(r, &q); err != nil || q.BindBase != nil {
+		t.Errorf("Bind() = %v, BindBase = %v, want nil", err, q.BindBase)
+	}
+}
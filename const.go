@@ -21,6 +21,39 @@ const (
 	FilterOperatorLessThan       FilterOperator = "lt"
 	FilterOperatorGreaterOrEqual FilterOperator = "ge"
 	FilterOperatorLessOrEqual    FilterOperator = "le"
+	// FilterOperatorBetween matches values within two inclusive bounds, e.g. age[bt]=18,65
+	FilterOperatorBetween FilterOperator = "bt"
+	// FilterOperatorNotBetween matches values outside two inclusive bounds
+	FilterOperatorNotBetween FilterOperator = "nbt"
+	// FilterOperatorBetweenExclusive matches values strictly between two bounds
+	FilterOperatorBetweenExclusive FilterOperator = "btx"
+	// FilterOperatorNotBetweenExclusive matches values outside two exclusive bounds,
+	// the bounds themselves included
+	FilterOperatorNotBetweenExclusive FilterOperator = "nbtx"
+	// FilterOperatorStartsWith matches values starting with a literal string, e.g. name[sw]=Jo
+	FilterOperatorStartsWith FilterOperator = "sw"
+	// FilterOperatorEndsWith matches values ending with a literal string
+	FilterOperatorEndsWith FilterOperator = "ew"
+	// FilterOperatorContains matches values containing a literal string
+	FilterOperatorContains FilterOperator = "ct"
+	// FilterOperatorILike is a case-insensitive lk
+	FilterOperatorILike FilterOperator = "ilk"
+	// FilterOperatorNotILike is a case-insensitive nlk
+	FilterOperatorNotILike FilterOperator = "nilk"
+	// FilterOperatorRegexp matches values against an RE2 regular expression, e.g. message[re]=^timeout
+	FilterOperatorRegexp FilterOperator = "re"
+	// FilterOperatorNotRegexp matches values not matching an RE2 regular expression
+	FilterOperatorNotRegexp FilterOperator = "nre"
+	// FilterOperatorContainsAll matches array columns containing every value, e.g. tags[all]=go,api
+	FilterOperatorContainsAll FilterOperator = "all"
+	// FilterOperatorOverlaps matches array columns sharing at least one value with the list
+	FilterOperatorOverlaps FilterOperator = "ov"
+	// FilterOperatorContainedBy matches array columns whose elements are all in the list
+	FilterOperatorContainedBy FilterOperator = "cb"
+	// FilterOperatorIsNull matches null values, e.g. deleted_at[isnull]
+	FilterOperatorIsNull FilterOperator = "isnull"
+	// FilterOperatorNotNull matches non-null values, e.g. deleted_at[notnull]
+	FilterOperatorNotNull FilterOperator = "notnull"
 )
 
 // Valid checks if the filter operator is valid.
@@ -38,14 +71,74 @@ func (o FilterOperator) Valid() error {
 		FilterOperatorGreaterThan,
 		FilterOperatorLessThan,
 		FilterOperatorGreaterOrEqual,
-		FilterOperatorLessOrEqual:
+		FilterOperatorLessOrEqual,
+		FilterOperatorBetween,
+		FilterOperatorNotBetween,
+		FilterOperatorBetweenExclusive,
+		FilterOperatorNotBetweenExclusive,
+		FilterOperatorStartsWith,
+		FilterOperatorEndsWith,
+		FilterOperatorContains,
+		FilterOperatorILike,
+		FilterOperatorNotILike,
+		FilterOperatorRegexp,
+		FilterOperatorNotRegexp,
+		FilterOperatorContainsAll,
+		FilterOperatorOverlaps,
+		FilterOperatorContainedBy,
+		FilterOperatorIsNull,
+		FilterOperatorNotNull:
 		return nil
 	}
 
-	return fmt.Errorf("invalid operator: %q", o)
+	return &ParseError{Code: ErrInvalidOperator, Operator: o, Err: fmt.Errorf("invalid operator: %q", o)}
 }
 
 // IsList returns true if the operator expects multiple values (comma-separated).
 func (o FilterOperator) IsList() bool {
-	return o == FilterOperatorIn || o == FilterOperatorNotIn || o == FilterOperatorInLike || o == FilterOperatorNotInLike
+	return o == FilterOperatorIn || o == FilterOperatorNotIn || o == FilterOperatorInLike || o == FilterOperatorNotInLike || o.IsRange() || o.IsArray()
+}
+
+// IsArray returns true if the operator compares a multi-valued (array) column
+// with a set of values, rather than a single value with a list.
+func (o FilterOperator) IsArray() bool {
+	return o == FilterOperatorContainsAll || o == FilterOperatorOverlaps || o == FilterOperatorContainedBy
+}
+
+// IsRange returns true if the operator compares the field against a lower and
+// an upper bound, given as exactly two comma-separated values.
+func (o FilterOperator) IsRange() bool {
+	return o == FilterOperatorBetween || o == FilterOperatorNotBetween || o == FilterOperatorBetweenExclusive || o == FilterOperatorNotBetweenExclusive
+}
+
+// IsPattern returns true if the operator matches the textual form of the field
+// against a LIKE pattern or a regular expression rather than comparing it to
+// typed values.
+func (o FilterOperator) IsPattern() bool {
+	switch o {
+	case FilterOperatorLike,
+		FilterOperatorNotLike,
+		FilterOperatorInLike,
+		FilterOperatorNotInLike,
+		FilterOperatorILike,
+		FilterOperatorNotILike,
+		FilterOperatorStartsWith,
+		FilterOperatorEndsWith,
+		FilterOperatorContains,
+		FilterOperatorRegexp,
+		FilterOperatorNotRegexp:
+		return true
+	}
+	return false
+}
+
+// IsRegexp returns true if the operator matches a regular expression.
+func (o FilterOperator) IsRegexp() bool {
+	return o == FilterOperatorRegexp || o == FilterOperatorNotRegexp
+}
+
+// IsNullCheck returns true if the operator tests the field for null and
+// compares it against no value.
+func (o FilterOperator) IsNullCheck() bool {
+	return o == FilterOperatorIsNull || o == FilterOperatorNotNull
 }
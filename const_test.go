@@ -22,6 +22,22 @@ func TestFilterOperatorValid(t *testing.T) {
 		{"Valid Less Than", FilterOperatorLessThan, false},
 		{"Valid Greater Or Equal", FilterOperatorGreaterOrEqual, false},
 		{"Valid Less Or Equal", FilterOperatorLessOrEqual, false},
+		{"Valid Between", FilterOperatorBetween, false},
+		{"Valid Not Between", FilterOperatorNotBetween, false},
+		{"Valid Between Exclusive", FilterOperatorBetweenExclusive, false},
+		{"Valid Not Between Exclusive", FilterOperatorNotBetweenExclusive, false},
+		{"Valid Starts With", FilterOperatorStartsWith, false},
+		{"Valid Ends With", FilterOperatorEndsWith, false},
+		{"Valid Contains", FilterOperatorContains, false},
+		{"Valid ILike", FilterOperatorILike, false},
+		{"Valid Not ILike", FilterOperatorNotILike, false},
+		{"Valid Regexp", FilterOperatorRegexp, false},
+		{"Valid Not Regexp", FilterOperatorNotRegexp, false},
+		{"Valid Contains All", FilterOperatorContainsAll, false},
+		{"Valid Overlaps", FilterOperatorOverlaps, false},
+		{"Valid Contained By", FilterOperatorContainedBy, false},
+		{"Valid Is Null", FilterOperatorIsNull, false},
+		{"Valid Not Null", FilterOperatorNotNull, false},
 		{"Invalid operator", FilterOperator("invalid"), true},
 		{"Empty operator", FilterOperator(""), true},
 	}
@@ -46,6 +62,12 @@ func TestFilterOperatorIsList(t *testing.T) {
 		{"Not In operator", FilterOperatorNotIn, true},
 		{"In Like operator", FilterOperatorInLike, true},
 		{"Not In Like operator", FilterOperatorNotInLike, true},
+		{"Between operator", FilterOperatorBetween, true},
+		{"Not Between Exclusive operator", FilterOperatorNotBetweenExclusive, true},
+		{"Contains All operator", FilterOperatorContainsAll, true},
+		{"Overlaps operator", FilterOperatorOverlaps, true},
+		{"Contained By operator", FilterOperatorContainedBy, true},
+		{"Is Null operator", FilterOperatorIsNull, false},
 		{"Equal operator", FilterOperatorEqual, false},
 		{"Not Equal operator", FilterOperatorNotEqual, false},
 		{"Like operator", FilterOperatorLike, false},
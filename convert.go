@@ -0,0 +1,149 @@
+package hapi
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ValueError reports the element of a Values collection that could not be converted.
+type ValueError struct {
+	Index int   // The position of the value in the collection
+	Value Value // The value that failed to convert
+	Err   error // The underlying conversion error
+}
+
+func (e *ValueError) Error() string {
+	return fmt.Sprintf("value %d: %v", e.Index, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// As converts v to type T. Supported types are strings, signed and unsigned
+// integers, floats, booleans, time.Duration (see time.ParseDuration),
+// time.Time (see Value.ParseTime), any type whose pointer implements
+// encoding.TextUnmarshaler, such as big.Rat for exact decimals or
+// netip.Addr, and pointers to those.
+//
+//	limit, err := hapi.As[uint](v)
+//	timeout, err := hapi.As[time.Duration](v)
+//	price, err := hapi.As[*big.Rat](v)
+//
+// Relative times are evaluated against the current time; use AsAt with
+// Result.Now to evaluate them against the clock of the parse, when set.
+func As[T any](v Value) (T, error) {
+	return AsAt[T](v, time.Now())
+}
+
+// AsAt converts v to type T like As, evaluating relative times against now.
+func AsAt[T any](v Value, now time.Time) (T, error) {
+	var res T
+	if err := convert(reflect.ValueOf(&res).Elem(), v, now); err != nil {
+		var zero T
+		return zero, err
+	}
+	return res, nil
+}
+
+// AsSlice converts every value of values to type T, see As.
+// It fails with a *ValueError identifying the first value that does not convert.
+func AsSlice[T any](values Values) ([]T, error) {
+	return AsSliceAt[T](values, time.Now())
+}
+
+// AsSliceAt converts every value of values to type T like AsSlice,
+// evaluating relative times against now.
+func AsSliceAt[T any](values Values, now time.Time) ([]T, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	res := make([]T, len(values))
+	for i, v := range values {
+		var err error
+		if res[i], err = AsAt[T](v, now); err != nil {
+			return nil, &ValueError{Index: i, Value: v, Err: err}
+		}
+	}
+	return res, nil
+}
+
+// convert converts v and stores it into fv, allocating pointers as needed.
+// Relative times are evaluated against now.
+func convert(fv reflect.Value, v Value, now time.Time) error {
+	if fv.Kind() == reflect.Pointer {
+		elem := reflect.New(fv.Type().Elem())
+		if err := convert(elem.Elem(), v, now); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	switch fv.Type() {
+	case timeType:
+		t, err := v.ParseTimeAt(now)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(string(v))
+		if err != nil {
+			return fmt.Errorf("invalid duration value %q", v)
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		if err := fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+			return fmt.Errorf("invalid %s value %q: %w", fv.Type(), v, err)
+		}
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(string(v))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(string(v), 10, fv.Type().Bits())
+		if err != nil {
+			return conversionError(fv.Type().String(), v, err)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(string(v), 10, fv.Type().Bits())
+		if err != nil {
+			return conversionError(fv.Type().String(), v, err)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(string(v), fv.Type().Bits())
+		if err != nil {
+			return conversionError(fv.Type().String(), v, err)
+		}
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(string(v))
+		if err != nil {
+			return conversionError("bool", v, err)
+		}
+		fv.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+
+	return nil
+}
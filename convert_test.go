@@ -0,0 +1,80 @@
+package hapi
+
+import (
+	"errors"
+	"math/big"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAs(t *testing.T) {
+	if got, err := As[uint8]("200"); got != 200 || err != nil {
+		t.Errorf("As[uint8](200) = %v, %v", got, err)
+	}
+	if _, err := As[uint8]("300"); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("As[uint8](300) error = %v, want strconv.ErrRange", err)
+	}
+	if _, err := As[uint]("-1"); err == nil {
+		t.Error("As[uint](-1) expected error, got nil")
+	}
+	if got, err := As[time.Duration]("1h30m"); got != 90*time.Minute || err != nil {
+		t.Errorf("As[time.Duration](1h30m) = %v, %v", got, err)
+	}
+	if _, err := As[time.Duration]("90"); err == nil {
+		t.Error("As[time.Duration](90) expected error, got nil")
+	}
+	if got, err := As[time.Time]("2024-01-31"); !got.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.Local)) || err != nil {
+		t.Errorf("As[time.Time](2024-01-31) = %v, %v", got, err)
+	}
+	if got, err := As[*big.Rat]("19.99"); err != nil || got.Cmp(big.NewRat(1999, 100)) != 0 {
+		t.Errorf("As[*big.Rat](19.99) = %v, %v", got, err)
+	}
+	if _, err := As[*big.Rat]("19,99"); err == nil {
+		t.Error("As[*big.Rat](19,99) expected error, got nil")
+	}
+	if got, err := As[netip.Addr]("192.0.2.1"); got != netip.MustParseAddr("192.0.2.1") || err != nil {
+		t.Errorf("As[netip.Addr](192.0.2.1) = %v, %v", got, err)
+	}
+	if got, err := As[*string]("x"); got == nil || *got != "x" || err != nil {
+		t.Errorf("As[*string](x) = %v, %v", got, err)
+	}
+	if got, err := As[int]("abc"); got != 0 || err == nil {
+		t.Errorf("As[int](abc) = %v, %v, want 0 and an error", got, err)
+	}
+	if _, err := As[[]int]("1"); err == nil {
+		t.Error("As[[]int] expected unsupported type error, got nil")
+	}
+}
+
+func TestValuesConversions(t *testing.T) {
+	ints, err := Values{"1", "2", "3"}.Ints()
+	if err != nil || !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Errorf("Ints() = %v, %v", ints, err)
+	}
+
+	floats, err := Values{"1.5", "-2"}.Float64s()
+	if err != nil || !reflect.DeepEqual(floats, []float64{1.5, -2}) {
+		t.Errorf("Float64s() = %v, %v", floats, err)
+	}
+
+	if got, err := Values(nil).Int64s(); got != nil || err != nil {
+		t.Errorf("Int64s() of no values = %v, %v, want nil, nil", got, err)
+	}
+
+	_, err = Values{"true", "maybe", "false"}.Bools()
+	var valueErr *ValueError
+	if !errors.As(err, &valueErr) || valueErr.Index != 1 || valueErr.Value != "maybe" {
+		t.Fatalf("Bools() error = %v, want *ValueError at index 1", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Bools() error does not unwrap to strconv.ErrSyntax: %v", err)
+	}
+
+	times, err := Values{"2024-01-31T00:00:00Z", "1706702400"}.Times()
+	if err != nil || len(times) != 2 || !times[1].Equal(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Times() = %v, %v", times, err)
+	}
+}
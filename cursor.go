@@ -0,0 +1,194 @@
+package hapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cursor is the decoded position of a keyset pagination cursor, read from the
+// cursor, after or before parameter when Options.CursorKey is set.
+type Cursor struct {
+	Values Values // Sort key values of the reference item, in the order of Result.Sorts
+	Before bool   // Whether the page ends before the reference item instead of starting after it
+}
+
+// cursorPayload is the signed content of a cursor token.
+type cursorPayload struct {
+	Sorts  string `json:"s"`           // The sorts the cursor was minted for, e.g. "name:asc,id:desc"
+	Values Values `json:"v"`           // The sort key values of the reference item
+	Before bool   `json:"b,omitempty"` // Whether the cursor points to the previous page
+}
+
+// NextCursor returns the cursor of the page of r following the item whose
+// sort key values are given, in the order of r.Sorts, signed with CursorKey.
+// It is minted from the last item of the current page.
+//
+// Values are formatted as query values: time.Time as RFC 3339, types
+// implementing encoding.TextMarshaler as their text, and other values with
+// fmt. Returns an error if cursor pagination is disabled or the number of
+// values does not match the sorts.
+func (o Options) NextCursor(r Result, values ...any) (string, error) {
+	return mintCursor(o.CursorKey, r.Sorts, values, false)
+}
+
+// PrevCursor returns the cursor of the page of r preceding the item whose
+// sort key values are given, in the order of r.Sorts, signed with CursorKey.
+// It is minted from the first item of the current page.
+func (o Options) PrevCursor(r Result, values ...any) (string, error) {
+	return mintCursor(o.CursorKey, r.Sorts, values, true)
+}
+
+// Keyset returns the predicate selecting the items of a cursor page, e.g.
+// "(a > x) or (a = x and b > y)" after an item sorted by a and b ascending.
+// Comparisons are reversed for a Before cursor, whose items must then be
+// fetched in the reverse order of Sorts and reversed back before display.
+// Reports false when the query has no cursor.
+//
+// Sort key columns must not be null, and the last sort should be a unique
+// key so that no two items share a position.
+func (r Result) Keyset() (Expr, bool) {
+	if r.Cursor == nil || len(r.Cursor.Values) != len(r.Sorts) || len(r.Sorts) == 0 {
+		return Expr{}, false
+	}
+
+	terms := make([]Expr, len(r.Sorts))
+	for i, sort := range r.Sorts {
+		operator := FilterOperatorGreaterThan
+		if (sort.Direction == SortDirectionDesc) != r.Cursor.Before {
+			operator = FilterOperatorLessThan
+		}
+
+		exprs := make([]Expr, 0, i+1)
+		for j, previous := range r.Sorts[:i] {
+			exprs = append(exprs, FilterExpr(Filter{Field: previous.Field, Column: previous.Column, Operator: FilterOperatorEqual, Values: Values{r.Cursor.Values[j]}}))
+		}
+		exprs = append(exprs, FilterExpr(Filter{Field: sort.Field, Column: sort.Column, Operator: operator, Values: Values{r.Cursor.Values[i]}}))
+
+		terms[i] = exprs[0]
+		if len(exprs) > 1 {
+			terms[i] = And(exprs...)
+		}
+	}
+
+	if len(terms) == 1 {
+		return terms[0], true
+	}
+	return Or(terms...), true
+}
+
+// mintCursor encodes a cursor for sorts and signs it with key.
+func mintCursor(key []byte, sorts Sorts, values []any, before bool) (string, error) {
+	if len(key) == 0 {
+		return "", fmt.Errorf("cursor pagination is disabled: Options.CursorKey is not set")
+	}
+	if len(sorts) == 0 {
+		return "", fmt.Errorf("cursor pagination requires at least one sort")
+	}
+	if len(values) != len(sorts) {
+		return "", fmt.Errorf("cursor expects %d sort key values, got %d", len(sorts), len(values))
+	}
+
+	payload := cursorPayload{Sorts: sortsKey(sorts), Values: make(Values, len(values)), Before: before}
+	for i, v := range values {
+		value, err := cursorValue(v)
+		if err != nil {
+			return "", fmt.Errorf("invalid value for sort field %q: %w", sorts[i].Field, err)
+		}
+		payload.Values[i] = value
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(key, encoded)), nil
+}
+
+// parseCursor verifies the signature of a cursor token and decodes it,
+// checking that it was minted for sorts.
+func parseCursor(token string, sorts Sorts, key []byte) (*Cursor, error) {
+	invalid := func(format string, args ...any) error {
+		return &ParseError{Code: ErrInvalidCursor, Value: Value(token), Err: fmt.Errorf(format, args...)}
+	}
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalid("invalid cursor format")
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, signCursor(key, encoded)) {
+		return nil, invalid("invalid cursor signature")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalid("invalid cursor encoding: %w", err)
+	}
+	var payload cursorPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, invalid("invalid cursor payload: %w", err)
+	}
+
+	if len(sorts) == 0 {
+		return nil, invalid("cursor pagination requires at least one sort")
+	}
+	if current := sortsKey(sorts); payload.Sorts != current || len(payload.Values) != len(sorts) {
+		return nil, invalid("cursor was issued for sort %q, not %q", payload.Sorts, current)
+	}
+
+	return &Cursor{Values: payload.Values, Before: payload.Before}, nil
+}
+
+// signCursor returns the HMAC-SHA256 of an encoded cursor payload.
+func signCursor(key []byte, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// sortsKey returns the canonical form of sorts, e.g. "name:asc,id:desc".
+func sortsKey(sorts Sorts) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		parts[i] = sort.Field + ":" + string(sort.Direction)
+	}
+	return strings.Join(parts, ",")
+}
+
+// cursorValue formats a sort key value of a result item as a query value.
+func cursorValue(v any) (Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", fmt.Errorf("sort key value cannot be nil")
+	case Value:
+		return v, nil
+	case string:
+		return Value(v), nil
+	case []byte:
+		return Value(v), nil
+	case time.Time:
+		return Value(v.Format(time.RFC3339Nano)), nil
+	case int:
+		return Value(strconv.Itoa(v)), nil
+	case int64:
+		return Value(strconv.FormatInt(v, 10)), nil
+	case float64:
+		return Value(strconv.FormatFloat(v, 'g', -1, 64)), nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		if err != nil {
+			return "", err
+		}
+		return Value(text), nil
+	}
+	return Value(fmt.Sprint(v)), nil
+}
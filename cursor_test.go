@@ -0,0 +1,186 @@
+package hapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	opts := Options{CursorKey: []byte("secret"), FieldMap: map[string]string{"created": "u.created_at", "id": "u.id"}}
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	first, err := ParseStrict("http://x/users?sort=created:desc,id:asc", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, err := opts.NextCursor(first, created, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev, err := opts.PrevCursor(first, created, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query  string
+		before bool
+	}{
+		{"cursor=" + next, false},
+		{"cursor=" + prev, true},
+		{"after=" + prev, false},
+		{"before=" + next, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			r, err := ParseStrict("http://x/users?"+tt.query+"&sort=created:desc,id:asc&page=4", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := &Cursor{Values: Values{"2024-05-01T12:00:00Z", "42"}, Before: tt.before}
+			if !reflect.DeepEqual(r.Cursor, want) {
+				t.Errorf("Cursor = %+v, want %+v", r.Cursor, want)
+			}
+			if r.Offset() != 0 {
+				t.Errorf("Offset() = %d, want 0 for a cursor page", r.Offset())
+			}
+		})
+	}
+}
+
+func TestResultKeyset(t *testing.T) {
+	sorts := Sorts{
+		{Field: "created", Column: "u.created_at", Direction: SortDirectionDesc},
+		{Field: "id", Direction: SortDirectionAsc},
+	}
+	leaf := func(s Sort, op FilterOperator, v Value) Expr {
+		return FilterExpr(Filter{Field: s.Field, Column: s.Column, Operator: op, Values: Values{v}})
+	}
+
+	r := Result{Sorts: sorts, Cursor: &Cursor{Values: Values{"2024", "42"}}}
+	want := Or(
+		leaf(sorts[0], FilterOperatorLessThan, "2024"),
+		And(leaf(sorts[0], FilterOperatorEqual, "2024"), leaf(sorts[1], FilterOperatorGreaterThan, "42")),
+	)
+	if got, ok := r.Keyset(); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Keyset() = %v, %v, want %v", got, ok, want)
+	}
+
+	r.Cursor.Before = true
+	want = Or(
+		leaf(sorts[0], FilterOperatorGreaterThan, "2024"),
+		And(leaf(sorts[0], FilterOperatorEqual, "2024"), leaf(sorts[1], FilterOperatorLessThan, "42")),
+	)
+	if got, ok := r.Keyset(); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("Keyset() before = %v, %v, want %v", got, ok, want)
+	}
+
+	r = Result{Sorts: sorts[1:], Cursor: &Cursor{Values: Values{"42"}}}
+	if got, ok := r.Keyset(); !ok || !reflect.DeepEqual(got, leaf(sorts[1], FilterOperatorGreaterThan, "42")) {
+		t.Errorf("Keyset() single sort = %v, %v", got, ok)
+	}
+
+	if _, ok := (Result{Sorts: sorts}).Keyset(); ok {
+		t.Error("Keyset() without cursor reported true")
+	}
+}
+
+func TestParse_CursorErrors(t *testing.T) {
+	opts := Options{CursorKey: []byte("secret")}
+	r, err := Parse("http://x/users?sort=name:asc", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := opts.NextCursor(r, "John")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	other := Options{CursorKey: []byte("other")}
+	forged, err := other.NextCursor(r, "Zoe")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{
+		"sort=name:asc&cursor=" + forged,
+		"sort=name:asc&cursor=" + payload,
+		"sort=name:asc&cursor=" + payload + "x." + signature,
+		"sort=name:desc&cursor=" + token,
+		"cursor=" + token,
+		"sort=name:asc&cursor",
+	} {
+		u := "http://x/users?" + query
+		_, err := ParseStrict(u, opts)
+		if err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", query)
+			continue
+		}
+		if !errors.Is(err, ErrInvalidCursor) && !errors.Is(err, ErrInvalidFormat) {
+			t.Errorf("ParseStrict(%q) error = %v, want an invalid cursor error", query, err)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", query, err)
+		}
+		if r.Cursor != nil {
+			t.Errorf("Parse(%q) Cursor = %+v, want nil", query, r.Cursor)
+		}
+	}
+
+	if _, err := ParseStrict("http://x/users?sort=name:asc&after="+token+"&before="+token, opts); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("ParseStrict() with two cursors error = %v, want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestParse_CursorDisabled(t *testing.T) {
+	r, err := ParseStrict("http://x/users?cursor=abc&sort=name:asc", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cursor != nil {
+		t.Errorf("Cursor = %+v, want nil without a cursor key", r.Cursor)
+	}
+	if f := r.Filters.GetFirstFromField("cursor"); f.Values.First() != "abc" {
+		t.Errorf("cursor should be parsed as a filter without a cursor key, got %v", r.Filters)
+	}
+
+	if _, err := (Options{}).NextCursor(r, "John"); err == nil {
+		t.Error("NextCursor() expected error without a cursor key, got nil")
+	}
+}
+
+// The cursor key is kept out of the Result, which may be logged.
+func TestResultHidesCursorKey(t *testing.T) {
+	r, err := Parse("http://x/users?sort=name:asc", Options{CursorKey: []byte("s3cr3t-key")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%+v %#v", r, r); strings.Contains(got, "s3cr3t-key") || strings.Contains(got, fmt.Sprint([]byte("s3cr3t-key"))) {
+		t.Errorf("Result leaks the cursor key: %s", got)
+	}
+}
+
+func TestNextCursorErrors(t *testing.T) {
+	opts := Options{CursorKey: []byte("secret")}
+	r := Result{}
+	if _, err := opts.NextCursor(r); err == nil {
+		t.Error("NextCursor() expected error without sorts, got nil")
+	}
+
+	r.Sorts = Sorts{{Field: "name", Direction: SortDirectionAsc}}
+	if _, err := opts.NextCursor(r, "a", "b"); err == nil {
+		t.Error("NextCursor() expected error for too many values, got nil")
+	}
+	if _, err := opts.NextCursor(r, nil); err == nil {
+		t.Error("NextCursor() expected error for a nil value, got nil")
+	}
+}
@@ -18,5 +18,5 @@ func (s SortDirection) Valid() error {
 		return nil
 	}
 
-	return fmt.Errorf("invalid sort direction: %q", s)
+	return &ParseError{Code: ErrInvalidDirection, Value: Value(s), Err: fmt.Errorf("invalid sort direction: %q", s)}
 }
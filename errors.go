@@ -0,0 +1,129 @@
+package hapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorCode identifies the kind of a ParseError. Codes are errors themselves,
+// so errors.Is(err, hapi.ErrFieldNotAllowed) reports whether err, or any error
+// it wraps or joins, has that code.
+type ErrorCode string
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Error codes of ParseError.
+const (
+	ErrInvalidFormat      ErrorCode = "invalid_format"       // A parameter is malformed, e.g. "per_page" without value or "name[gt"
+	ErrInvalidEscape      ErrorCode = "invalid_escape"       // A value is not correctly percent-encoded
+	ErrInvalidOperator    ErrorCode = "invalid_operator"     // A filter operator is unknown
+	ErrOperatorNotAllowed ErrorCode = "operator_not_allowed" // A filter operator is not allowed for its field
+	ErrFieldNotAllowed    ErrorCode = "field_not_allowed"    // A field may not be filtered on
+	ErrInvalidValue       ErrorCode = "invalid_value"        // A value does not suit its field or operator
+	ErrInvalidSort        ErrorCode = "invalid_sort"         // A sort is malformed
+	ErrInvalidDirection   ErrorCode = "invalid_direction"    // A sort direction is unknown
+	ErrSortNotAllowed     ErrorCode = "sort_not_allowed"     // A field may not be sorted on
+	ErrMaxDepthExceeded   ErrorCode = "max_depth_exceeded"   // Filter groups or expressions are nested too deeply
+	ErrInvalidExpression  ErrorCode = "invalid_expression"   // An RSQL or OData expression has a syntax error
+	ErrUnsupportedOption  ErrorCode = "unsupported_option"   // An OData system query option is not supported
+	ErrInvalidCursor      ErrorCode = "invalid_cursor"       // A pagination cursor is forged, corrupted or issued for other sorts
+	ErrIncludeNotAllowed  ErrorCode = "include_not_allowed"  // A JSON:API relationship path may not be included
+	ErrSelectNotAllowed   ErrorCode = "select_not_allowed"   // A field may not be selected
+)
+
+// ParseError reports a rejected query parameter. The fields that do not apply
+// to an error are left empty.
+type ParseError struct {
+	Code     ErrorCode      // The kind of error
+	Param    string         // The offending parameter, as it appears in the query string
+	Field    string         // The field concerned
+	Operator FilterOperator // The filter operator concerned
+	Value    Value          // The offending value
+	Position int            // The byte offset of a syntax error in an RSQL or OData expression
+	Err      error          // The underlying error, which carries the message; the code is the message without it
+}
+
+func (e *ParseError) Error() string {
+	if e.Err == nil {
+		return string(e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the code of the error.
+func (e *ParseError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	return ok && code == e.Code
+}
+
+// withField sets the field of err when it is a *ParseError without one.
+func withField(err error, field string) error {
+	if parseErr, ok := err.(*ParseError); ok && parseErr.Field == "" {
+		parseErr.Field = field
+	}
+	return err
+}
+
+// ParseErrors lists every parameter rejected by a strict parse, in query
+// order. It is the error returned by ParseStrict and ParseFromRequestStrict
+// when the query is invalid, so that clients can fix all their parameters at
+// once. Like errors.Join, it unwraps to the individual errors.
+type ParseErrors []*ParseError
+
+// Error returns the message of a single error unchanged, and one
+// "param: message" line per error otherwise.
+func (e ParseErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = fmt.Sprintf("%s: %v", err.Param, err)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e ParseErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// errorCollector gathers the rejected parameters of a strict parse.
+// In lenient mode, rejected parameters are silently dropped.
+type errorCollector struct {
+	strict bool
+	errs   ParseErrors
+}
+
+// add records that param was rejected because of err. Errors that are not
+// a *ParseError are reported as ErrInvalidFormat.
+func (c *errorCollector) add(param string, err error) {
+	if !c.strict {
+		return
+	}
+
+	parseErr, ok := err.(*ParseError)
+	if !ok {
+		parseErr = &ParseError{Code: ErrInvalidFormat, Err: err}
+	}
+	parseErr.Param = param
+	c.errs = append(c.errs, parseErr)
+}
+
+// err returns the collected errors, or nil when there are none.
+func (c *errorCollector) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
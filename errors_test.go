@@ -0,0 +1,174 @@
+package hapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseStrict_CollectsAllErrors(t *testing.T) {
+	opts := Options{
+		AllowedFilters: []string{"name", "age"},
+		AllowedSorts:   []string{"name"},
+		FieldTypes:     map[string]FieldType{"age": FieldTypeInt},
+	}
+
+	_, err := ParseStrict("http://x/users?name[xx]=a&salary=1&age[gt]=abc&sort=email:asc,name:up&name=%zz&per_page&name=ok", opts)
+	if err == nil {
+		t.Fatal("ParseStrict() expected error, got nil")
+	}
+
+	var parseErrs ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("error %T is not a ParseErrors", err)
+	}
+
+	wantParams := []string{
+		"name[xx]=a",
+		"salary=1",
+		"age[gt]=abc",
+		"sort=email:asc,name:up",
+		"sort=email:asc,name:up",
+		"name=%zz",
+		"per_page",
+	}
+	if len(parseErrs) != len(wantParams) {
+		t.Fatalf("got %d errors, want %d:\n%v", len(parseErrs), len(wantParams), err)
+	}
+	for i, want := range wantParams {
+		if parseErrs[i].Param != want {
+			t.Errorf("errors[%d].Param = %q, want %q", i, parseErrs[i].Param, want)
+		}
+	}
+
+	// Each issue is listed on its own line with its parameter.
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != len(wantParams) || lines[1] != `salary=1: filtering by field "salary" is not allowed` {
+		t.Errorf("Error() =\n%s", err)
+	}
+
+	// The individual errors are reachable like with errors.Join.
+	if len(parseErrs.Unwrap()) != len(wantParams) {
+		t.Errorf("Unwrap() returned %d errors, want %d", len(parseErrs.Unwrap()), len(wantParams))
+	}
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Param != "name[xx]=a" {
+		t.Errorf("errors.As(*ParseError) = %v, want the first error", parseErr)
+	}
+}
+
+// A single error keeps its message unchanged.
+func TestParseStrict_SingleErrorMessage(t *testing.T) {
+	_, err := ParseStrict("http://x/users?salary=1", Options{AllowedFilters: []string{"name"}})
+	if want := `filtering by field "salary" is not allowed`; err == nil || err.Error() != want {
+		t.Errorf("ParseStrict() error = %v, want %q", err, want)
+	}
+}
+
+// A ParseError without underlying error falls back to its code as message.
+func TestParseError_WithoutErr(t *testing.T) {
+	err := &ParseError{Code: ErrInvalidValue, Param: "age=x"}
+	if err.Error() != "invalid_value" || err.Unwrap() != nil || !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ParseError{} = %q, want the code as message", err.Error())
+	}
+
+	errs := ParseErrors{err, {Code: ErrInvalidSort, Param: "sort=x", Err: errors.New("bad sort")}}
+	if want := "age=x: invalid_value\nsort=x: bad sort"; errs.Error() != want {
+		t.Errorf("ParseErrors.Error() = %q, want %q", errs.Error(), want)
+	}
+}
+
+func TestParseStrict_ODataCollectsAllErrors(t *testing.T) {
+	_, err := ParseStrict("http://x/users?$top=-1&$skip=x&$search=a", Options{Syntax: SyntaxOData})
+
+	var parseErrs ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 3 {
+		t.Fatalf("ParseStrict() error = %v, want 3 errors", err)
+	}
+}
+
+func TestParseStrict_ErrorCodes(t *testing.T) {
+	opts := Options{
+		AllowedFilters:   []string{"name", "age", "status", "created_at"},
+		AllowedSorts:     []string{"name"},
+		AllowedOperators: map[string][]FilterOperator{"status": {FilterOperatorEqual}},
+		FieldTypes:       map[string]FieldType{"age": FieldTypeInt},
+		RSQLParam:        "q",
+		MaxDepth:         1,
+	}
+
+	tests := []struct {
+		query string
+		want  ParseError
+	}{
+		{"per_page", ParseError{Code: ErrInvalidFormat, Param: "per_page"}},
+		{"name[gt", ParseError{Code: ErrInvalidFormat, Param: "name[gt"}},
+		{"name=%zz", ParseError{Code: ErrInvalidEscape, Param: "name=%zz", Operator: FilterOperatorEqual, Value: "%zz"}},
+		{"name[xx]=a", ParseError{Code: ErrInvalidOperator, Param: "name[xx]=a", Field: "name", Operator: "xx"}},
+		{"status[ne]=a", ParseError{Code: ErrOperatorNotAllowed, Param: "status[ne]=a", Field: "status", Operator: FilterOperatorNotEqual}},
+		{"salary=1", ParseError{Code: ErrFieldNotAllowed, Param: "salary=1", Field: "salary", Operator: FilterOperatorEqual}},
+		{"age[gt]=abc", ParseError{Code: ErrInvalidValue, Param: "age[gt]=abc", Field: "age", Operator: FilterOperatorGreaterThan, Value: "abc"}},
+		{"name[isnull]=maybe", ParseError{Code: ErrInvalidValue, Param: "name[isnull]=maybe", Field: "name", Operator: FilterOperatorIsNull, Value: "maybe"}},
+		{"sort=name", ParseError{Code: ErrInvalidSort, Param: "sort=name", Value: "name"}},
+		{"sort=name:up", ParseError{Code: ErrInvalidDirection, Param: "sort=name:up", Field: "name", Value: "up"}},
+		{"sort=email:asc", ParseError{Code: ErrSortNotAllowed, Param: "sort=email:asc", Field: "email"}},
+		{"or[0][and][1][name]=a", ParseError{Code: ErrMaxDepthExceeded, Param: "or[0][and][1][name]=a", Field: "name"}},
+		{"q=name==a;(age=gt=1", ParseError{Code: ErrInvalidExpression, Param: "q=name==a;(age=gt=1", Position: 17}},
+		{"q=((name==a))", ParseError{Code: ErrMaxDepthExceeded, Param: "q=((name==a))", Position: 2}},
+		{"q=name=zz=a", ParseError{Code: ErrInvalidOperator, Param: "q=name=zz=a", Field: "name", Operator: "zz"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseStrict("http://x/users?"+tt.query, opts)
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("ParseStrict() error = %v, want a *ParseError", err)
+			}
+			if !errors.Is(err, tt.want.Code) {
+				t.Errorf("errors.Is(err, %s) = false, got code %s", tt.want.Code, parseErr.Code)
+			}
+
+			got := *parseErr
+			got.Err = nil
+			if got != tt.want {
+				t.Errorf("ParseError = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseStrict_ODataErrorCodes(t *testing.T) {
+	opts := Options{Syntax: SyntaxOData}
+
+	tests := []struct {
+		query string
+		code  ErrorCode
+	}{
+		{"$top", ErrInvalidFormat},
+		{"$top=x", ErrInvalidValue},
+		{"$search=a", ErrUnsupportedOption},
+		{"$orderby=name%20up", ErrInvalidDirection},
+		{"$filter=name%20eq", ErrInvalidExpression},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseStrict("http://x/users?"+tt.query, opts)
+		if !errors.Is(err, tt.code) {
+			t.Errorf("ParseStrict(%q) error = %v, want code %s", tt.query, err, tt.code)
+		}
+	}
+}
+
+func TestValidErrorCodes(t *testing.T) {
+	if err := FilterOperator("xx").Valid(); !errors.Is(err, ErrInvalidOperator) {
+		t.Errorf("FilterOperator.Valid() error = %v, want ErrInvalidOperator", err)
+	}
+	if err := SortDirection("up").Valid(); !errors.Is(err, ErrInvalidDirection) {
+		t.Errorf("SortDirection.Valid() error = %v, want ErrInvalidDirection", err)
+	}
+	if _, err := parseSortFromString("a:b:c"); !errors.Is(err, ErrInvalidSort) {
+		t.Errorf("parseSortFromString() error = %v, want ErrInvalidSort", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/ermos/hapi"
 )
@@ -44,7 +45,7 @@ func ExampleParseFromRequest() {
 
 		result, err := hapi.ParseFromRequest(r, opts)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			hapi.WriteProblem(w, err)
 			return
 		}
 
@@ -171,3 +172,78 @@ func ExampleParse_multipleSorts() {
 	// Sort 2: age desc
 	// Sort 3: created_at asc
 }
+
+func ExampleSchemaFor() {
+	type User struct {
+		Name      string    `hapi:"name,filter=eq|lk,sort"`
+		Age       int       `hapi:"age,filter=gt|ge|lt|le,sort"`
+		Status    string    `hapi:"status,filter=eq|in,enum=active|pending"`
+		CreatedAt time.Time `hapi:"created,sort,column=created_at"`
+	}
+
+	opts, err := hapi.SchemaFor[User]()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	url := "http://api.example.com/users?age[ge]=18&status[in]=active,pending&sort=created:desc"
+	result, err := hapi.ParseStrict(url, *opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Filters: %d\n", len(result.Filters))
+	fmt.Printf("Sort: %s (%s) %s\n", result.Sorts[0].Field, result.Sorts[0].ColumnName(), result.Sorts[0].Direction)
+
+	_, err = hapi.ParseStrict("http://api.example.com/users?age[lk]=1%25", *opts)
+	fmt.Printf("Error: %v\n", err)
+
+	// Output:
+	// Filters: 2
+	// Sort: created (created_at) desc
+	// Error: operator "lk" is not allowed for field "age"
+}
+
+func ExampleBind() {
+	type UserQuery struct {
+		Name     *string  `hapi:"name"`
+		MinAge   *int     `hapi:"age,ge"`
+		MaxAge   *int     `hapi:"age,le"`
+		Statuses []string `hapi:"status,in"`
+	}
+
+	result, err := hapi.Parse("http://api.example.com/users?age[ge]=18&status[in]=active,pending", hapi.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var q UserQuery
+	if err := hapi.Bind(result, &q); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("Name set: %t\n", q.Name != nil)
+	fmt.Printf("MinAge: %d\n", *q.MinAge)
+	fmt.Printf("MaxAge set: %t\n", q.MaxAge != nil)
+	fmt.Printf("Statuses: %v\n", q.Statuses)
+
+	// Output:
+	// Name set: false
+	// MinAge: 18
+	// MaxAge set: false
+	// Statuses: [active pending]
+}
+
+func ExampleResult_Where() {
+	url := "http://api.example.com/tasks?age[ge]=18&or[0][status]=active&or[0][owner]=me&not[0][role][in]=admin,root"
+
+	result, err := hapi.Parse(url, hapi.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(result.Where())
+
+	// Output:
+	// (age ge [18] and (status eq [active] or owner eq [me]) and not role in [admin root])
+}
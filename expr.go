@@ -0,0 +1,200 @@
+package hapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LogicalOperator represents the boolean connective of an expression node.
+type LogicalOperator string
+
+const (
+	LogicalAnd LogicalOperator = "and"
+	LogicalOr  LogicalOperator = "or"
+	LogicalNot LogicalOperator = "not"
+)
+
+// Valid checks if the logical operator is valid.
+// Returns an error if the operator is not recognized.
+func (o LogicalOperator) Valid() error {
+	switch o {
+	case LogicalAnd, LogicalOr, LogicalNot:
+		return nil
+	}
+
+	return fmt.Errorf("invalid logical operator: %q", o)
+}
+
+// Expr represents a node of a boolean filter expression.
+// A leaf holds a single Filter; other nodes combine their children with a
+// logical operator. A NOT node has exactly one child.
+type Expr struct {
+	Operator LogicalOperator // The logical operator, empty for a leaf
+	Filter   *Filter         // The filter condition of a leaf
+	Children []Expr          // The operands of a logical node
+}
+
+// FilterExpr returns a leaf expression holding f.
+func FilterExpr(f Filter) Expr {
+	return Expr{Filter: &f}
+}
+
+// And returns an expression matching when all of exprs match.
+func And(exprs ...Expr) Expr {
+	return Expr{Operator: LogicalAnd, Children: exprs}
+}
+
+// Or returns an expression matching when any of exprs matches.
+func Or(exprs ...Expr) Expr {
+	return Expr{Operator: LogicalOr, Children: exprs}
+}
+
+// Not returns an expression matching when expr does not match.
+func Not(expr Expr) Expr {
+	return Expr{Operator: LogicalNot, Children: []Expr{expr}}
+}
+
+// IsLeaf returns true if the expression holds a single filter.
+func (e Expr) IsLeaf() bool {
+	return e.Filter != nil
+}
+
+// Filters returns every filter of the expression tree, in order of appearance.
+func (e Expr) Filters() Filters {
+	if e.Filter != nil {
+		return Filters{*e.Filter}
+	}
+
+	var filters Filters
+	for _, child := range e.Children {
+		filters = append(filters, child.Filters()...)
+	}
+	return filters
+}
+
+// String returns a human-readable form of the expression, e.g.
+// "(status eq [active] or owner eq [me])".
+func (e Expr) String() string {
+	if e.Filter != nil {
+		return fmt.Sprintf("%s %s %v", e.Filter.Field, e.Filter.Operator, e.Filter.Values)
+	}
+
+	if e.Operator == LogicalNot && len(e.Children) == 1 {
+		return "not " + e.Children[0].String()
+	}
+
+	parts := make([]string, len(e.Children))
+	for i, child := range e.Children {
+		parts[i] = child.String()
+	}
+	return "(" + strings.Join(parts, " "+string(e.Operator)+" ") + ")"
+}
+
+// allLeaves reports whether every expression of exprs is a leaf.
+func allLeaves(exprs []Expr) bool {
+	for _, e := range exprs {
+		if !e.IsLeaf() {
+			return false
+		}
+	}
+	return true
+}
+
+// groupKey identifies a filter group, e.g. "or[0]".
+type groupKey struct {
+	operator LogicalOperator
+	id       string
+}
+
+// exprBuilder assembles an Expr from filters addressed by group paths,
+// preserving the order in which groups and filters first appear.
+type exprBuilder struct {
+	operator LogicalOperator
+	expr     *Expr
+	children []*exprBuilder
+	groups   map[groupKey]*exprBuilder
+	dropped  bool // Whether the group had a rejected member and is left out
+}
+
+// add places f in the group addressed by path, creating groups as needed.
+// Filters of a dropped group are ignored.
+func (b *exprBuilder) add(path []groupKey, f Filter) {
+	node := b
+	for _, key := range path {
+		node = node.group(key)
+		if node.dropped {
+			return
+		}
+	}
+	node.children = append(node.children, &exprBuilder{expr: &Expr{Filter: &f}})
+}
+
+// drop leaves out the top-level group of path, whose member at path was
+// rejected: keeping the other members would change the meaning of the group,
+// e.g. "not[0][role]=admin&not[0][secret]=x" would exclude every admin.
+// Does nothing for a top-level filter.
+func (b *exprBuilder) drop(path []groupKey) {
+	if len(path) > 0 {
+		b.group(path[0]).dropped = true
+	}
+}
+
+// group returns the child group identified by key, creating it as needed.
+func (b *exprBuilder) group(key groupKey) *exprBuilder {
+	child, ok := b.groups[key]
+	if !ok {
+		child = &exprBuilder{operator: key.operator}
+		if b.groups == nil {
+			b.groups = make(map[groupKey]*exprBuilder)
+		}
+		b.groups[key] = child
+		b.children = append(b.children, child)
+	}
+	return child
+}
+
+// addExpr appends a complete expression to the top level.
+func (b *exprBuilder) addExpr(e Expr) {
+	b.children = append(b.children, &exprBuilder{expr: &e})
+}
+
+// addParsed adds an expression parsed from an expression syntax (RSQL,
+// OData, ...) to the top level. A plain conjunction of filters is added as
+// flat filters, which are returned; anything else is added as a whole and
+// reported as grouped.
+func (b *exprBuilder) addParsed(e Expr) (Filters, bool) {
+	if !e.IsLeaf() && (e.Operator != LogicalAnd || !allLeaves(e.Children)) {
+		b.addExpr(e)
+		return nil, true
+	}
+
+	filters := e.Filters()
+	for _, f := range filters {
+		b.add(nil, f)
+	}
+	return filters, false
+}
+
+// build returns the expression assembled so far.
+func (b *exprBuilder) build() Expr {
+	if b.expr != nil {
+		return *b.expr
+	}
+
+	children := make([]Expr, 0, len(b.children))
+	for _, child := range b.children {
+		if !child.dropped {
+			children = append(children, child.build())
+		}
+	}
+
+	if b.operator == LogicalNot {
+		// A NOT group negates the conjunction of its members.
+		if len(children) == 1 {
+			return Not(children[0])
+		}
+		return Not(And(children...))
+	}
+
+	return Expr{Operator: b.operator, Children: children}
+}
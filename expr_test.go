@@ -0,0 +1,61 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLogicalOperatorValid(t *testing.T) {
+	for _, op := range []LogicalOperator{LogicalAnd, LogicalOr, LogicalNot} {
+		if err := op.Valid(); err != nil {
+			t.Errorf("LogicalOperator(%q).Valid() = %v, want nil", op, err)
+		}
+	}
+	if err := LogicalOperator("xor").Valid(); err == nil {
+		t.Error(`LogicalOperator("xor").Valid() = nil, want error`)
+	}
+}
+
+func TestExprFiltersAndString(t *testing.T) {
+	status := Filter{Field: "status", Operator: FilterOperatorEqual, Values: Values{"active"}}
+	owner := Filter{Field: "owner", Operator: FilterOperatorEqual, Values: Values{"me"}}
+	role := Filter{Field: "role", Operator: FilterOperatorNotEqual, Values: Values{"admin"}}
+
+	e := And(Or(FilterExpr(status), FilterExpr(owner)), Not(FilterExpr(role)))
+
+	if want := (Filters{status, owner, role}); !reflect.DeepEqual(e.Filters(), want) {
+		t.Errorf("Filters() = %v, want %v", e.Filters(), want)
+	}
+
+	want := "((status eq [active] or owner eq [me]) and not role ne [admin])"
+	if got := e.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	if e.IsLeaf() || !FilterExpr(status).IsLeaf() {
+		t.Error("IsLeaf() reported the wrong node kind")
+	}
+}
+
+func TestExprBuilder(t *testing.T) {
+	a := Filter{Field: "a"}
+	b := Filter{Field: "b"}
+	c := Filter{Field: "c"}
+	d := Filter{Field: "d"}
+
+	root := &exprBuilder{operator: LogicalAnd}
+	root.add(nil, a)
+	root.add([]groupKey{{LogicalOr, "0"}}, b)
+	root.add([]groupKey{{LogicalNot, "0"}}, c)
+	root.add([]groupKey{{LogicalOr, "0"}}, d)
+	root.add([]groupKey{{LogicalNot, "0"}}, d)
+
+	want := And(
+		FilterExpr(a),
+		Or(FilterExpr(b), FilterExpr(d)),
+		Not(And(FilterExpr(c), FilterExpr(d))),
+	)
+	if got := root.build(); !reflect.DeepEqual(got, want) {
+		t.Errorf("build() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,78 @@
+package hapi
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Fields represents the projection of a query: the fields to return.
+type Fields []Field
+
+// Field represents a field selected by the fields parameter.
+type Field struct {
+	Name   string `json:"name"` // The field name, as sent by the client
+	Column string `json:"-"`    // The storage column mapped from Name (empty without Options.FieldMap)
+}
+
+// ColumnName returns the storage column of the field, falling back to Name when unmapped.
+func (f Field) ColumnName() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.Name
+}
+
+// Names returns the names of the fields, in order.
+func (f Fields) Names() []string {
+	names := make([]string, len(f))
+	for i, field := range f {
+		names[i] = field.Name
+	}
+	return names
+}
+
+// Has returns true if the projection includes the named field.
+func (f Fields) Has(name string) bool {
+	return slices.ContainsFunc(f, func(field Field) bool {
+		return field.Name == name
+	})
+}
+
+// buildField validates a requested field against the options and resolves
+// its storage column.
+func buildField(name string, opts Options) (Field, error) {
+	column, ok := opts.column(name)
+	if !ok || len(opts.AllowedFields) > 0 && !slices.Contains(opts.AllowedFields, name) {
+		return Field{}, &ParseError{Code: ErrSelectNotAllowed, Field: name, Err: fmt.Errorf("selecting field %q is not allowed", name)}
+	}
+	return Field{Name: name, Column: column}, nil
+}
+
+// projection returns the fields to select: the required fields followed by
+// the requested ones, or by the default fields when none was requested.
+// Returns nil, selecting every field, when there is no projection at all.
+func projection(requested Fields, opts Options) Fields {
+	if len(requested) == 0 {
+		if len(opts.DefaultFields) == 0 {
+			return nil
+		}
+		for _, name := range opts.DefaultFields {
+			column, _ := opts.column(name)
+			requested = append(requested, Field{Name: name, Column: column})
+		}
+	}
+
+	fields := make(Fields, 0, len(opts.RequiredFields)+len(requested))
+	for _, name := range opts.RequiredFields {
+		if !fields.Has(name) {
+			column, _ := opts.column(name)
+			fields = append(fields, Field{Name: name, Column: column})
+		}
+	}
+	for _, field := range requested {
+		if !fields.Has(field.Name) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,189 @@
+package hapi
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldType represents the type of the values a field accepts.
+type FieldType string
+
+const (
+	FieldTypeString FieldType = "string"
+	FieldTypeInt    FieldType = "int"
+	FieldTypeInt64  FieldType = "int64"
+	FieldTypeUint   FieldType = "uint"
+	FieldTypeFloat  FieldType = "float"
+	FieldTypeBool   FieldType = "bool"
+	// FieldTypeTime accepts the time formats of Value.ParseTimeAt, including
+	// relative expressions such as "now-7d".
+	FieldTypeTime FieldType = "time"
+	// FieldTypeUUID accepts UUIDs in their canonical 8-4-4-4-12 hexadecimal form.
+	FieldTypeUUID FieldType = "uuid"
+	// FieldTypeEnum accepts the values listed in Options.EnumValues for the field.
+	FieldTypeEnum FieldType = "enum"
+)
+
+// Valid checks if the field type is valid.
+// Returns an error if the type is not recognized.
+func (t FieldType) Valid() error {
+	switch t {
+	case FieldTypeString,
+		FieldTypeInt,
+		FieldTypeInt64,
+		FieldTypeUint,
+		FieldTypeFloat,
+		FieldTypeBool,
+		FieldTypeTime,
+		FieldTypeUUID,
+		FieldTypeEnum:
+		return nil
+	}
+
+	return fmt.Errorf("invalid field type: %q", t)
+}
+
+// validate checks that v is a valid value for the type.
+// enum lists the accepted values when the type is FieldTypeEnum, and relative
+// times are resolved against now.
+func (t FieldType) validate(v Value, enum []string, now time.Time) error {
+	var err error
+
+	switch t {
+	case FieldTypeString:
+		return nil
+	case FieldTypeInt:
+		_, err = strconv.Atoi(string(v))
+	case FieldTypeInt64:
+		_, err = strconv.ParseInt(string(v), 10, 64)
+	case FieldTypeUint:
+		_, err = strconv.ParseUint(string(v), 10, 64)
+	case FieldTypeFloat:
+		// NaN and infinities parse but are not comparable values.
+		var f float64
+		if f, err = strconv.ParseFloat(string(v), 64); err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return fmt.Errorf("expected finite %s, got %q", t, v)
+		}
+	case FieldTypeBool:
+		_, err = strconv.ParseBool(string(v))
+	case FieldTypeTime:
+		_, err = v.ParseTimeAt(now)
+	case FieldTypeUUID:
+		if !isUUID(string(v)) {
+			return fmt.Errorf("expected uuid, got %q", v)
+		}
+	case FieldTypeEnum:
+		if !slices.Contains(enum, string(v)) {
+			return fmt.Errorf("expected one of %q, got %q", enum, v)
+		}
+	default:
+		return t.Valid()
+	}
+
+	if err != nil {
+		return fmt.Errorf("expected %s, got %q", t, v)
+	}
+	return nil
+}
+
+// normalize validates v like validate and returns its canonical form:
+// numbers without leading zeros or sign ("007" is "7"), booleans as "true" or
+// "false", UUIDs in lower case, and times as RFC 3339 timestamps. Times are
+// resolved against now, so relative expressions such as "now-7d" are
+// evaluated once, at parse time.
+func (t FieldType) normalize(v Value, enum []string, now time.Time) (Value, error) {
+	// Times are parsed once, here, rather than validated first.
+	if t == FieldTypeTime {
+		tm, err := v.ParseTimeAt(now)
+		if err != nil {
+			return "", fmt.Errorf("expected %s, got %q", t, v)
+		}
+		return Value(tm.Format(time.RFC3339Nano)), nil
+	}
+
+	if err := t.validate(v, enum, now); err != nil {
+		return "", err
+	}
+
+	switch t {
+	case FieldTypeInt, FieldTypeInt64:
+		n, _ := strconv.ParseInt(string(v), 10, 64)
+		return Value(strconv.FormatInt(n, 10)), nil
+	case FieldTypeUint:
+		n, _ := strconv.ParseUint(string(v), 10, 64)
+		return Value(strconv.FormatUint(n, 10)), nil
+	case FieldTypeFloat:
+		f, _ := strconv.ParseFloat(string(v), 64)
+		return Value(strconv.FormatFloat(f, 'g', -1, 64)), nil
+	case FieldTypeBool:
+		b, _ := strconv.ParseBool(string(v))
+		return Value(strconv.FormatBool(b)), nil
+	case FieldTypeUUID:
+		return Value(strings.ToLower(string(v))), nil
+	}
+	return v, nil
+}
+
+// compare compares two values of an ordered type and returns -1, 0 or +1.
+// It returns false when the type is not ordered or a value does not parse.
+func (t FieldType) compare(a, b Value) (int, bool) {
+	switch t {
+	case FieldTypeInt, FieldTypeInt64:
+		x, errA := strconv.ParseInt(string(a), 10, 64)
+		y, errB := strconv.ParseInt(string(b), 10, 64)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	case FieldTypeUint:
+		x, errA := strconv.ParseUint(string(a), 10, 64)
+		y, errB := strconv.ParseUint(string(b), 10, 64)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	case FieldTypeFloat:
+		x, errA := strconv.ParseFloat(string(a), 64)
+		y, errB := strconv.ParseFloat(string(b), 64)
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return cmp.Compare(x, y), true
+	case FieldTypeTime:
+		x, errA := time.Parse(time.RFC3339, string(a))
+		y, errB := time.Parse(time.RFC3339, string(b))
+		if errA != nil || errB != nil {
+			return 0, false
+		}
+		return x.Compare(y), true
+	}
+
+	return 0, false
+}
+
+// isUUID reports whether s is a UUID in canonical 8-4-4-4-12 hexadecimal form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch i {
+		case 8, 13, 18, 23:
+			if c != '-' {
+				return false
+			}
+		default:
+			if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F') {
+				return false
+			}
+		}
+	}
+	return true
+}
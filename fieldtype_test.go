@@ -0,0 +1,75 @@
+package hapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFieldTypeValid(t *testing.T) {
+	for _, ft := range []FieldType{
+		FieldTypeString, FieldTypeInt, FieldTypeInt64, FieldTypeFloat,
+		FieldTypeBool, FieldTypeTime, FieldTypeUUID, FieldTypeEnum,
+	} {
+		if err := ft.Valid(); err != nil {
+			t.Errorf("FieldType(%q).Valid() = %v, want nil", ft, err)
+		}
+	}
+
+	if err := FieldType("decimal").Valid(); err == nil {
+		t.Error(`FieldType("decimal").Valid() = nil, want error`)
+	}
+}
+
+func TestFieldTypeValidate(t *testing.T) {
+	tests := []struct {
+		fieldType FieldType
+		value     Value
+		wantErr   bool
+	}{
+		{FieldTypeString, "anything", false},
+		{FieldTypeString, "", false},
+		{FieldTypeInt, "42", false},
+		{FieldTypeInt, "-7", false},
+		{FieldTypeInt, "abc", true},
+		{FieldTypeInt, "", true},
+		{FieldTypeInt, "1.5", true},
+		{FieldTypeInt64, "9223372036854775807", false},
+		{FieldTypeInt64, "9223372036854775808", true},
+		{FieldTypeFloat, "3.14", false},
+		{FieldTypeFloat, "pi", true},
+		{FieldTypeFloat, "NaN", true},
+		{FieldTypeFloat, "Inf", true},
+		{FieldTypeFloat, "-infinity", true},
+		{FieldTypeBool, "true", false},
+		{FieldTypeBool, "0", false},
+		{FieldTypeBool, "yes", true},
+		{FieldTypeTime, "2024-01-02T15:04:05Z", false},
+		{FieldTypeTime, "2024-01-02T15:04:05+02:00", false},
+		{FieldTypeTime, "yesterday-ish", true},
+		{FieldTypeUUID, "123e4567-e89b-12d3-a456-426614174000", false},
+		{FieldTypeUUID, "123E4567-E89B-12D3-A456-426614174000", false},
+		{FieldTypeUUID, "123e4567e89b12d3a456426614174000", true},
+		{FieldTypeUUID, "123e4567-e89b-12d3-a456-42661417400g", true},
+		{FieldType("decimal"), "1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.fieldType)+"/"+string(tt.value), func(t *testing.T) {
+			err := tt.fieldType.validate(tt.value, nil, time.Now())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldTypeValidateEnum(t *testing.T) {
+	enum := []string{"active", "pending"}
+
+	if err := FieldTypeEnum.validate("active", enum, time.Time{}); err != nil {
+		t.Errorf("validate(active) = %v, want nil", err)
+	}
+	if err := FieldTypeEnum.validate("banned", enum, time.Time{}); err == nil {
+		t.Error("validate(banned) = nil, want error")
+	}
+}
@@ -1,13 +1,30 @@
 package hapi
 
+import "regexp"
+
 // Filters represents a collection of Filter conditions.
 type Filters []Filter
 
 // Filter represents a single filter condition with a field, operator, and values.
+//
+// A comparison with an empty string, such as "deleted_at=", holds a single
+// empty value, while a null check ("deleted_at[isnull]") uses the isnull or
+// notnull operator and holds no value at all.
 type Filter struct {
-	Field    string         // The field name to filter on
+	Field    string         // The field name to filter on, as sent by the client
+	Column   string         // The storage column mapped from Field (empty without Options.FieldMap)
 	Operator FilterOperator // The comparison operator
-	Values   Values         // The values to compare against
+	Values   Values         // The values to compare against (nil for isnull and notnull)
+	Regexp   *regexp.Regexp // The compiled regular expression of re and nre filters
+	Escaped  bool           // Whether the pattern values were escaped with EscapeLike, e.g. from RSQL wildcards
+}
+
+// ColumnName returns the storage column of the filter, falling back to Field when unmapped.
+func (f Filter) ColumnName() string {
+	if f.Column != "" {
+		return f.Column
+	}
+	return f.Field
 }
 
 // GetFromField returns all filters that match the specified field name.
@@ -0,0 +1,90 @@
+package hapi
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// jsonAPIParamNames are the reserved parameters of the JSON:API syntax,
+// which nests pagination in the page family of parameters.
+var jsonAPIParamNames = ParamNames{
+	Page:    "page[number]",
+	PerPage: "page[size]",
+	Offset:  "page[offset]",
+	Limit:   "page[limit]",
+	Cursor:  "page[cursor]",
+	After:   "page[after]",
+	Before:  "page[before]",
+}
+
+// parseJSONAPISort parses a JSON:API sort field, where a leading minus sign
+// sorts in descending order: "-created".
+func parseJSONAPISort(value string) (Sort, error) {
+	field, desc := strings.CutPrefix(value, "-")
+	if field == "" {
+		return Sort{}, &ParseError{Code: ErrInvalidSort, Value: Value(value), Err: fmt.Errorf("invalid sort format: expected 'field' or '-field', got %q", value)}
+	}
+
+	direction := SortDirectionAsc
+	if desc {
+		direction = SortDirectionDesc
+	}
+	return Sort{Field: field, Direction: direction}, nil
+}
+
+// parseFieldset parses a JSON:API sparse fieldset such as
+// "fields[articles]=title,body" into its resource type and fields.
+// An empty list requests no fields at all.
+func parseFieldset(key, value string) (string, []string, error) {
+	_, segments, ok := splitKey(key)
+	if !ok || len(segments) != 1 || segments[0] == "" {
+		return "", nil, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid fieldset format: %s", key)}
+	}
+
+	fields, err := splitList(value)
+	if err != nil {
+		return "", nil, err
+	}
+	return segments[0], fields, nil
+}
+
+// parseIncludes parses a JSON:API include list such as
+// "author,comments.author" into its relationship paths, checking them against
+// the allowed includes. In lenient mode, paths that are not allowed are
+// skipped instead of failing the whole list.
+func parseIncludes(value string, opts Options, strict bool) ([]string, error) {
+	paths, err := splitList(value)
+	if err != nil {
+		return nil, err
+	}
+
+	includes := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if len(opts.AllowedIncludes) > 0 && !slices.Contains(opts.AllowedIncludes, path) {
+			if strict {
+				return nil, &ParseError{Code: ErrIncludeNotAllowed, Value: Value(path), Err: fmt.Errorf("including %q is not allowed", path)}
+			}
+			continue
+		}
+		includes = append(includes, path)
+	}
+	return includes, nil
+}
+
+// splitList unescapes a comma-separated list of names, dropping blank items.
+func splitList(value string) ([]string, error) {
+	unescaped, err := url.QueryUnescape(value)
+	if err != nil {
+		return nil, &ParseError{Code: ErrInvalidEscape, Value: Value(value), Err: fmt.Errorf("failed to unescape value %q: %w", value, err)}
+	}
+
+	items := make([]string, 0)
+	for _, item := range strings.Split(unescaped, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
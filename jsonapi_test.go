@@ -0,0 +1,154 @@
+package hapi
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseJSONAPISort(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Sort
+	}{
+		{"created", Sort{Field: "created", Direction: SortDirectionAsc}},
+		{"-created", Sort{Field: "created", Direction: SortDirectionDesc}},
+		{"author.name", Sort{Field: "author.name", Direction: SortDirectionAsc}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseJSONAPISort(tt.input)
+		if err != nil {
+			t.Errorf("parseJSONAPISort(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseJSONAPISort(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+
+	if _, err := parseJSONAPISort("-"); err == nil {
+		t.Error(`parseJSONAPISort("-") expected error, got nil`)
+	}
+}
+
+func TestParse_JSONAPI(t *testing.T) {
+	opts := Options{Syntax: SyntaxJSONAPI}
+
+	r, err := ParseStrict("http://x/articles?filter[status]=published&filter[views][gt]=100&sort=-created,title&page[number]=3&page[size]=20&fields[articles]=title,body&fields[people]=&include=author,comments.author&include=author&page=9", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilters := Filters{
+		{Field: "status", Operator: FilterOperatorEqual, Values: Values{"published"}},
+		{Field: "views", Operator: FilterOperatorGreaterThan, Values: Values{"100"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+	wantSorts := Sorts{
+		{Field: "created", Direction: SortDirectionDesc},
+		{Field: "title", Direction: SortDirectionAsc},
+	}
+	if !reflect.DeepEqual(r.Sorts, wantSorts) {
+		t.Errorf("Sorts = %v, want %v", r.Sorts, wantSorts)
+	}
+	if r.Page != 3 || r.PerPage != 20 {
+		t.Errorf("Page/PerPage = %d/%d, want 3/20", r.Page, r.PerPage)
+	}
+	wantFieldsets := map[string][]string{"articles": {"title", "body"}, "people": {}}
+	if !reflect.DeepEqual(r.Fieldsets, wantFieldsets) {
+		t.Errorf("Fieldsets = %v, want %v", r.Fieldsets, wantFieldsets)
+	}
+	if want := []string{"author", "comments.author"}; !reflect.DeepEqual(r.Includes, want) {
+		t.Errorf("Includes = %v, want %v", r.Includes, want)
+	}
+}
+
+func TestParse_JSONAPICursor(t *testing.T) {
+	opts := Options{Syntax: SyntaxJSONAPI, CursorKey: []byte("secret")}
+
+	first, err := ParseStrict("http://x/articles?sort=-created", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := opts.NextCursor(first, "2024-05-01T00:00:00Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ParseStrict("http://x/articles?sort=-created&page[cursor]="+token, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (&Cursor{Values: Values{"2024-05-01T00:00:00Z"}}); !reflect.DeepEqual(r.Cursor, want) {
+		t.Errorf("Cursor = %+v, want %+v", r.Cursor, want)
+	}
+}
+
+func TestParse_JSONAPIValidation(t *testing.T) {
+	opts := Options{
+		Syntax:          SyntaxJSONAPI,
+		AllowedIncludes: []string{"author"},
+		AllowedSorts:    []string{"created"},
+	}
+
+	for _, query := range []string{
+		"include=author,comments",
+		"sort=-views",
+		"sort=-",
+		"fields[articles][x]=title",
+		"filter[name][xx]=a",
+	} {
+		u := "http://x/articles?" + query
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", query)
+		}
+		if _, err := Parse(u, opts); err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", query, err)
+		}
+	}
+
+	_, err := ParseStrict("http://x/articles?include=comments", opts)
+	if !errors.Is(err, ErrIncludeNotAllowed) {
+		t.Errorf("ParseStrict() error = %v, want %v", err, ErrIncludeNotAllowed)
+	}
+
+	r, err := Parse("http://x/articles?include=author,comments", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"author"}; !reflect.DeepEqual(r.Includes, want) {
+		t.Errorf("Includes = %v, want %v", r.Includes, want)
+	}
+}
+
+// Encoders such as url.Values.Encode percent-encode the brackets of keys.
+func TestParse_JSONAPIEncodedKeys(t *testing.T) {
+	query := url.Values{
+		"page[number]":     {"3"},
+		"page[size]":       {"10"},
+		"filter[name][eq]": {"bob"},
+		"fields[articles]": {"title"},
+	}.Encode()
+
+	r, err := ParseStrict("http://x/articles?"+query, Options{Syntax: SyntaxJSONAPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Page != 3 || r.PerPage != 10 {
+		t.Errorf("Page/PerPage = %d/%d, want 3/10", r.Page, r.PerPage)
+	}
+	if want := (Filters{{Field: "name", Operator: FilterOperatorEqual, Values: Values{"bob"}}}); !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %v, want %v", r.Filters, want)
+	}
+	if want := map[string][]string{"articles": {"title"}}; !reflect.DeepEqual(r.Fieldsets, want) {
+		t.Errorf("Fieldsets = %v, want %v", r.Fieldsets, want)
+	}
+
+	if _, err := ParseStrict("http://x/articles?filter%ZZname%5D=bob", Options{Syntax: SyntaxJSONAPI}); !errors.Is(err, ErrInvalidEscape) {
+		t.Errorf("ParseStrict() error = %v, want %v for a malformed key", err, ErrInvalidEscape)
+	}
+}
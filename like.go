@@ -0,0 +1,29 @@
+package hapi
+
+import "strings"
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike escapes the LIKE wildcards "%" and "_" of s, and the backslash
+// used to escape them, so that s matches literally inside a LIKE pattern.
+// The resulting pattern must be evaluated with "\" as escape character.
+func EscapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// LikePattern returns the LIKE pattern matching v under the operator.
+// The literal values of sw, ew and ct are escaped with EscapeLike and wrapped
+// in "%" wildcards; the values of the other pattern operators already are
+// patterns and are returned unchanged.
+func (o FilterOperator) LikePattern(v Value) string {
+	switch o {
+	case FilterOperatorStartsWith:
+		return EscapeLike(string(v)) + "%"
+	case FilterOperatorEndsWith:
+		return "%" + EscapeLike(string(v))
+	case FilterOperatorContains:
+		return "%" + EscapeLike(string(v)) + "%"
+	}
+	return string(v)
+}
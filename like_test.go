@@ -0,0 +1,68 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"John", "John"},
+		{"50%", `50\%`},
+		{"snake_case", `snake\_case`},
+		{`C:\temp`, `C:\\temp`},
+		{`\%_`, `\\\%\_`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLike(tt.input); got != tt.want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFilterOperatorLikePattern(t *testing.T) {
+	tests := []struct {
+		operator FilterOperator
+		value    Value
+		want     string
+	}{
+		{FilterOperatorStartsWith, "50%", `50\%%`},
+		{FilterOperatorEndsWith, "_x", `%\_x`},
+		{FilterOperatorContains, "go", "%go%"},
+		{FilterOperatorLike, "Jo%", "Jo%"},
+		{FilterOperatorILike, "jo_", "jo_"},
+		{FilterOperatorInLike, "%api", "%api"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.operator.LikePattern(tt.value); got != tt.want {
+			t.Errorf("%s.LikePattern(%q) = %q, want %q", tt.operator, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParse_StringMatching(t *testing.T) {
+	opts := *NewOptions(WithFieldTypes(map[string]FieldType{"code": FieldTypeInt}))
+
+	r, err := ParseStrict("http://x/users?name[sw]=Jo&email[ew]=@example.com&title[ct]=50%25&city[ilk]=par%25&country[nilk]=fr%25&code[ct]=42", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Filters{
+		{Field: "name", Operator: FilterOperatorStartsWith, Values: Values{"Jo"}},
+		{Field: "email", Operator: FilterOperatorEndsWith, Values: Values{"@example.com"}},
+		{Field: "title", Operator: FilterOperatorContains, Values: Values{"50%"}},
+		{Field: "city", Operator: FilterOperatorILike, Values: Values{"par%"}},
+		{Field: "country", Operator: FilterOperatorNotILike, Values: Values{"fr%"}},
+		// String matching compares the textual form of typed fields.
+		{Field: "code", Operator: FilterOperatorContains, Values: Values{"42"}},
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %#v, want %#v", r.Filters, want)
+	}
+}
@@ -0,0 +1,444 @@
+package hapi
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// odataOperators maps OData comparison operators onto FilterOperator.
+var odataOperators = map[string]FilterOperator{
+	"eq": FilterOperatorEqual,
+	"ne": FilterOperatorNotEqual,
+	"gt": FilterOperatorGreaterThan,
+	"ge": FilterOperatorGreaterOrEqual,
+	"lt": FilterOperatorLessThan,
+	"le": FilterOperatorLessOrEqual,
+	"in": FilterOperatorIn,
+}
+
+// parseODataQuery parses the OData system query options $filter, $orderby,
+// $top and $skip. Other parameters are ignored, except unsupported system
+// query options which are reported in strict mode.
+func parseODataQuery(rawQuery string, opts Options, strict bool) (Result, error) {
+	maxPerPage := opts.maxPerPage()
+	result := newResult(opts)
+
+	root := &exprBuilder{operator: LogicalAnd}
+	grouped := false
+	var skip *int
+	errs := errorCollector{strict: strict}
+
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+
+		key, value, hasValue := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if !strings.HasPrefix(key, "$") {
+			continue
+		}
+
+		if !hasValue {
+			errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s option format: %s", key, param)})
+			continue
+		}
+
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			errs.add(param, &ParseError{Code: ErrInvalidEscape, Value: Value(value), Err: fmt.Errorf("failed to unescape value %q: %w", value, err)})
+			continue
+		}
+		value = unescaped
+
+		switch key {
+		case "$filter":
+			expr, err := parseODataFilter(value, opts, opts.maxDepth())
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			filters, isGrouped := root.addParsed(expr)
+			result.Filters = append(result.Filters, filters...)
+			grouped = grouped || isGrouped
+		case "$orderby":
+			for _, item := range strings.Split(value, ",") {
+				sort, err := parseODataOrderBy(item)
+				if err == nil {
+					sort, err = buildSort(sort, opts)
+				}
+				if err != nil {
+					errs.add(param, err)
+					continue
+				}
+
+				result.Sorts = append(result.Sorts, sort)
+			}
+		case "$top":
+			top, err := strconv.Atoi(value)
+			if err != nil || top < 0 {
+				errs.add(param, &ParseError{Code: ErrInvalidValue, Value: Value(value), Err: fmt.Errorf("invalid $top value: %q", value)})
+				continue
+			}
+			if top == 0 {
+				// An empty page is not supported: a zero PerPage means no limit.
+				errs.add(param, &ParseError{Code: ErrInvalidValue, Value: Value(value), Err: fmt.Errorf("invalid $top value: %q: $top must be at least 1", value)})
+				continue
+			}
+
+			result.PerPage = min(top, maxPerPage)
+		case "$skip":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				errs.add(param, &ParseError{Code: ErrInvalidValue, Value: Value(value), Err: fmt.Errorf("invalid $skip value: %q", value)})
+				continue
+			}
+
+			skip = &n
+		default:
+			errs.add(param, &ParseError{Code: ErrUnsupportedOption, Err: fmt.Errorf("unsupported query option %q", key)})
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		return Result{}, err
+	}
+
+	if skip != nil {
+		result.offset = skip
+		result.Page = *skip/result.PerPage + 1
+	}
+
+	if grouped {
+		expr := root.build()
+		result.Expr = &expr
+	}
+
+	return result, nil
+}
+
+// parseODataOrderBy parses a $orderby item such as "name" or "name desc".
+func parseODataOrderBy(item string) (Sort, error) {
+	fields := strings.Fields(item)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Sort{}, &ParseError{Code: ErrInvalidSort, Value: Value(item), Err: fmt.Errorf("invalid $orderby item: %q", item)}
+	}
+
+	direction := SortDirectionAsc
+	if len(fields) == 2 {
+		direction = SortDirection(fields[1])
+		if err := direction.Valid(); err != nil {
+			return Sort{}, withField(err, fields[0])
+		}
+	}
+
+	return Sort{Field: fields[0], Direction: direction}, nil
+}
+
+// parseODataFilter parses an OData $filter expression such as
+// "age ge 18 and startswith(name,'Jo')" into a boolean expression.
+//
+// It supports the eq, ne, gt, ge, lt, le and in comparisons, the and, or and
+// not operators, parentheses, and the startswith, endswith, contains and
+// matchesPattern functions, which are translated into sw, ew, ct and re
+// filters. "eq null" and "ne null" are translated into isnull and notnull
+// filters.
+//
+// Every comparison is validated against opts like a bracket filter.
+func parseODataFilter(input string, opts Options, maxDepth int) (Expr, error) {
+	p := odataParser{input: input, opts: opts, maxDepth: maxDepth}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return Expr{}, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return Expr{}, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+
+	return expr, nil
+}
+
+// odataParser is a recursive descent parser for OData $filter expressions.
+type odataParser struct {
+	input    string
+	pos      int
+	depth    int
+	maxDepth int
+	opts     Options
+}
+
+// errorf returns an ErrInvalidExpression error at the current position.
+func (p *odataParser) errorf(format string, args ...any) *ParseError {
+	return &ParseError{
+		Code:     ErrInvalidExpression,
+		Position: p.pos,
+		Err:      fmt.Errorf("invalid $filter expression at position %d: %s", p.pos, fmt.Sprintf(format, args...)),
+	}
+}
+
+// enter increments the nesting depth, failing once it exceeds the maximum.
+func (p *odataParser) enter() error {
+	p.depth++
+	if p.depth > p.maxDepth {
+		err := p.errorf("nesting exceeds the maximum depth of %d", p.maxDepth)
+		err.Code = ErrMaxDepthExceeded
+		return err
+	}
+	return nil
+}
+
+// parseOr parses operands separated by "or".
+func (p *odataParser) parseOr() (Expr, error) {
+	return p.parseList(LogicalOr, p.parseAnd)
+}
+
+// parseAnd parses operands separated by "and".
+func (p *odataParser) parseAnd() (Expr, error) {
+	return p.parseList(LogicalAnd, p.parseUnary)
+}
+
+// parseList parses operands separated by the op keyword and combines them with op.
+func (p *odataParser) parseList(op LogicalOperator, operand func() (Expr, error)) (Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := operand()
+		if err != nil {
+			return Expr{}, err
+		}
+		exprs = append(exprs, expr)
+
+		if !p.consumeKeyword(string(op)) {
+			break
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Expr{Operator: op, Children: exprs}, nil
+}
+
+// parseUnary parses "not" followed by an operand, or a primary expression.
+// Each "not" counts as a nesting level, like parentheses.
+func (p *odataParser) parseUnary() (Expr, error) {
+	if p.consumeKeyword("not") {
+		if err := p.enter(); err != nil {
+			return Expr{}, err
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return Expr{}, err
+		}
+		p.depth--
+		return Not(expr), nil
+	}
+
+	return p.parsePrimary()
+}
+
+// parsePrimary parses a parenthesized expression, a function call or a comparison.
+func (p *odataParser) parsePrimary() (Expr, error) {
+	p.skipSpaces()
+
+	if p.consume('(') {
+		if err := p.enter(); err != nil {
+			return Expr{}, err
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return Expr{}, err
+		}
+		if !p.consume(')') {
+			return Expr{}, p.errorf("missing closing parenthesis")
+		}
+		p.depth--
+		return expr, nil
+	}
+
+	name := p.readWord()
+	if name == "" {
+		return Expr{}, p.errorf("expected field name")
+	}
+
+	if p.consume('(') {
+		return p.parseFunction(name)
+	}
+
+	return p.parseComparison(name)
+}
+
+// parseComparison parses "field operator value" or "field in (value, ...)".
+func (p *odataParser) parseComparison(field string) (Expr, error) {
+	p.skipSpaces()
+	word := p.readWord()
+	operator, ok := odataOperators[word]
+	if !ok {
+		return Expr{}, p.errorf("unsupported comparison operator %q", word)
+	}
+
+	var values Values
+	if (operator == FilterOperatorEqual || operator == FilterOperatorNotEqual) && p.consumeKeyword("null") {
+		// "eq null" and "ne null" are null checks rather than comparisons.
+		operator = FilterOperatorIsNull
+		if word == "ne" {
+			operator = FilterOperatorNotNull
+		}
+	} else if operator == FilterOperatorIn {
+		if !p.consume('(') {
+			return Expr{}, p.errorf("expected \"(\" after in")
+		}
+		for {
+			value, err := p.parseLiteral()
+			if err != nil {
+				return Expr{}, err
+			}
+			values = append(values, value)
+
+			if p.consume(',') {
+				continue
+			}
+			if p.consume(')') {
+				break
+			}
+			return Expr{}, p.errorf("expected \",\" or \")\" in value list")
+		}
+	} else {
+		value, err := p.parseLiteral()
+		if err != nil {
+			return Expr{}, err
+		}
+		values = Values{value}
+	}
+
+	filter, err := buildFilter(field, operator, values, p.opts)
+	if err != nil {
+		return Expr{}, err
+	}
+	return FilterExpr(filter), nil
+}
+
+// odataFunctions maps OData string functions onto FilterOperator.
+var odataFunctions = map[string]FilterOperator{
+	"startswith":     FilterOperatorStartsWith,
+	"endswith":       FilterOperatorEndsWith,
+	"contains":       FilterOperatorContains,
+	"matchesPattern": FilterOperatorRegexp,
+}
+
+// parseFunction parses the arguments of a string function call, e.g.
+// "startswith(name,'Jo')", and translates it into a string-matching filter.
+func (p *odataParser) parseFunction(name string) (Expr, error) {
+	operator, ok := odataFunctions[name]
+	if !ok {
+		return Expr{}, p.errorf("unsupported function %q", name)
+	}
+
+	p.skipSpaces()
+	field := p.readWord()
+	if field == "" {
+		return Expr{}, p.errorf("expected field name")
+	}
+	if !p.consume(',') {
+		return Expr{}, p.errorf("expected \",\" in %s arguments", name)
+	}
+
+	p.skipSpaces()
+	if p.pos >= len(p.input) || p.input[p.pos] != '\'' {
+		return Expr{}, p.errorf("%s expects a string literal", name)
+	}
+	value, err := p.parseLiteral()
+	if err != nil {
+		return Expr{}, err
+	}
+	if !p.consume(')') {
+		return Expr{}, p.errorf("missing closing parenthesis")
+	}
+
+	filter, err := buildFilter(field, operator, Values{value}, p.opts)
+	if err != nil {
+		return Expr{}, err
+	}
+	return FilterExpr(filter), nil
+}
+
+// parseLiteral parses a single-quoted string, where a doubled quote escapes a quote, or
+// an unquoted literal such as a number, a boolean or a date.
+func (p *odataParser) parseLiteral() (Value, error) {
+	p.skipSpaces()
+
+	if p.pos < len(p.input) && p.input[p.pos] == '\'' {
+		p.pos++
+
+		var sb strings.Builder
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			p.pos++
+			if c != '\'' {
+				sb.WriteByte(c)
+				continue
+			}
+			if p.pos < len(p.input) && p.input[p.pos] == '\'' {
+				sb.WriteByte('\'')
+				p.pos++
+				continue
+			}
+			return Value(sb.String()), nil
+		}
+		return "", p.errorf("unterminated string literal")
+	}
+
+	word := p.readWord()
+	switch word {
+	case "":
+		return "", p.errorf("expected value")
+	case "null":
+		return "", p.errorf("null is only supported with eq and ne")
+	}
+	return Value(word), nil
+}
+
+// consumeKeyword consumes keyword when it is the next word.
+func (p *odataParser) consumeKeyword(keyword string) bool {
+	start := p.pos
+	p.skipSpaces()
+	if p.readWord() == keyword {
+		return true
+	}
+	p.pos = start
+	return false
+}
+
+// consume consumes c, after optional spaces, when it is the next byte.
+func (p *odataParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// readWord consumes and returns the next run of bytes that are not spaces,
+// parentheses, commas or quotes.
+func (p *odataParser) readWord() string {
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(" (),'", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *odataParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
@@ -0,0 +1,202 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseODataFilter(t *testing.T) {
+	leaf := func(field string, op FilterOperator, values ...Value) Expr {
+		return FilterExpr(Filter{Field: field, Operator: op, Values: values})
+	}
+
+	tests := []struct {
+		input string
+		want  Expr
+	}{
+		{"name eq 'John'", leaf("name", FilterOperatorEqual, "John")},
+		{"name eq 'O''Brien'", leaf("name", FilterOperatorEqual, "O'Brien")},
+		{"age ge 18", leaf("age", FilterOperatorGreaterOrEqual, "18")},
+		{"price lt 9.99", leaf("price", FilterOperatorLessThan, "9.99")},
+		{"created gt 2024-01-01T00:00:00Z", leaf("created", FilterOperatorGreaterThan, "2024-01-01T00:00:00Z")},
+		{"status in ('a', 'b')", leaf("status", FilterOperatorIn, "a", "b")},
+		{"startswith(name,'Jo')", leaf("name", FilterOperatorStartsWith, "Jo")},
+		{"endswith(email, '@example.com')", leaf("email", FilterOperatorEndsWith, "@example.com")},
+		{"contains(title,'50%')", leaf("title", FilterOperatorContains, "50%")},
+		{
+			"age ge 18 and startswith(name,'Jo')",
+			And(leaf("age", FilterOperatorGreaterOrEqual, "18"), leaf("name", FilterOperatorStartsWith, "Jo")),
+		},
+		{
+			"status eq 'a' or status eq 'b' and age gt 1",
+			Or(
+				leaf("status", FilterOperatorEqual, "a"),
+				And(leaf("status", FilterOperatorEqual, "b"), leaf("age", FilterOperatorGreaterThan, "1")),
+			),
+		},
+		{
+			"(status eq 'a' or status eq 'b') and not (age gt 1)",
+			And(
+				Or(leaf("status", FilterOperatorEqual, "a"), leaf("status", FilterOperatorEqual, "b")),
+				Not(leaf("age", FilterOperatorGreaterThan, "1")),
+			),
+		},
+		{"notes eq 'x'", leaf("notes", FilterOperatorEqual, "x")},
+		{"deleted_at eq null", leaf("deleted_at", FilterOperatorIsNull)},
+		{"deleted_at ne null", leaf("deleted_at", FilterOperatorNotNull)},
+		{"name eq 'null'", leaf("name", FilterOperatorEqual, "null")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseODataFilter(tt.input, Options{}, defaultMaxDepth)
+			if err != nil {
+				t.Fatalf("parseODataFilter() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseODataFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseODataFilterErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"name",
+		"name eq",
+		"name like 'a'",
+		"name eq 'John",
+		"(name eq 'a'",
+		"name eq 'a' and",
+		"status in 'a'",
+		"status in ('a'",
+		"substringof('a',name)",
+		"startswith(name,Jo)",
+		"matchesPattern(name,'(')",
+		"age gt null",
+		"((((a eq 1))))",
+	} {
+		if _, err := parseODataFilter(input, Options{}, defaultMaxDepth); err == nil {
+			t.Errorf("parseODataFilter(%q) expected error, got nil", input)
+		}
+	}
+}
+
+// Chained "not" count against the maximum depth like parentheses.
+func TestParseODataFilterNotDepth(t *testing.T) {
+	if _, err := parseODataFilter("not not not a eq 1", Options{}, defaultMaxDepth); err != nil {
+		t.Errorf("parseODataFilter() at maximum depth: %v", err)
+	}
+	for _, input := range []string{
+		"not not not not a eq 1",
+		"not (not (a eq 1))",
+		strings.Repeat("not ", 100000) + "a eq 1",
+	} {
+		if _, err := parseODataFilter(input, Options{}, defaultMaxDepth); !errors.Is(err, ErrMaxDepthExceeded) {
+			t.Errorf("parseODataFilter(%.30q) error = %v, want %v", input, err, ErrMaxDepthExceeded)
+		}
+	}
+}
+
+func TestParse_OData(t *testing.T) {
+	opts := Options{Syntax: SyntaxOData, MaxPerPage: 50}
+
+	r, err := ParseStrict("http://x/users?$filter=age%20ge%2018%20and%20startswith(name,'Jo')&$orderby=name%20desc,%20age&$top=20&$skip=40&name=ignored", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilters := Filters{
+		{Field: "age", Operator: FilterOperatorGreaterOrEqual, Values: Values{"18"}},
+		{Field: "name", Operator: FilterOperatorStartsWith, Values: Values{"Jo"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+	wantSorts := Sorts{
+		{Field: "name", Direction: SortDirectionDesc},
+		{Field: "age", Direction: SortDirectionAsc},
+	}
+	if !reflect.DeepEqual(r.Sorts, wantSorts) {
+		t.Errorf("Sorts = %v, want %v", r.Sorts, wantSorts)
+	}
+	if r.PerPage != 20 || r.Page != 3 || r.Offset() != 40 {
+		t.Errorf("PerPage/Page/Offset = %d/%d/%d, want 20/3/40", r.PerPage, r.Page, r.Offset())
+	}
+}
+
+func TestParse_ODataPagination(t *testing.T) {
+	opts := Options{Syntax: SyntaxOData, MaxPerPage: 50}
+
+	r, err := ParseStrict("http://x/users?$top=500&$skip=15", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PerPage != 50 {
+		t.Errorf("PerPage = %d, want $top capped to MaxPerPage 50", r.PerPage)
+	}
+	if r.Offset() != 15 || r.Page != 1 {
+		t.Errorf("Offset/Page = %d/%d, want 15/1", r.Offset(), r.Page)
+	}
+
+	r, err = Parse("http://x/users?$top=0", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PerPage != defaultPerPage {
+		t.Errorf("PerPage = %d, want $top=0 ignored in lenient mode", r.PerPage)
+	}
+
+	r, err = ParseStrict("http://x/users", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PerPage != defaultPerPage || r.Offset() != 0 {
+		t.Errorf("PerPage/Offset = %d/%d, want defaults", r.PerPage, r.Offset())
+	}
+}
+
+func TestParse_ODataGroupedFilter(t *testing.T) {
+	r, err := ParseStrict("http://x/users?$filter=status eq 'active' or owner eq 'me'", Options{Syntax: SyntaxOData})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := And(Or(
+		FilterExpr(Filter{Field: "status", Operator: FilterOperatorEqual, Values: Values{"active"}}),
+		FilterExpr(Filter{Field: "owner", Operator: FilterOperatorEqual, Values: Values{"me"}}),
+	))
+	if len(r.Filters) != 0 || r.Expr == nil || !reflect.DeepEqual(*r.Expr, want) {
+		t.Errorf("Filters = %v, Expr = %v, want %v", r.Filters, r.Expr, want)
+	}
+}
+
+func TestParse_ODataValidation(t *testing.T) {
+	opts := Options{
+		Syntax:         SyntaxOData,
+		AllowedFilters: []string{"name"},
+		AllowedSorts:   []string{"name"},
+	}
+
+	for _, u := range []string{
+		"http://x/users?$filter=salary gt 1",
+		"http://x/users?$filter=name eq",
+		"http://x/users?$orderby=salary desc",
+		"http://x/users?$orderby=name sideways",
+		"http://x/users?$top=abc",
+		"http://x/users?$top=0",
+		"http://x/users?$skip=-1",
+		"http://x/users?$expand=orders",
+		"http://x/users?$filter",
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+		if _, err := Parse(u, opts); err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", u, err)
+		}
+	}
+}
@@ -1,17 +1,133 @@
 package hapi
 
-// Default pagination values applied when Options leaves them unset.
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"time"
+)
+
+// Default values applied when Options leaves them unset.
 const (
 	defaultPerPage    = 10
 	defaultMaxPerPage = 100
+	defaultMaxDepth   = 3
+
+	defaultMaxPatternLength = 256
+)
+
+// Syntax represents the query parameter convention a query is written in.
+type Syntax string
+
+const (
+	// SyntaxDefault is the native syntax: field[operator]=value, sort=field:direction, page and per_page.
+	SyntaxDefault Syntax = ""
+	// SyntaxOData reads the OData system query options $filter, $orderby, $top and $skip.
+	SyntaxOData Syntax = "odata"
+	// SyntaxJSONAPI reads the JSON:API conventions: filter[field][operator]=value,
+	// sort=-field, page[number], page[size], fields[type] and include.
+	SyntaxJSONAPI Syntax = "jsonapi"
+)
+
+// OperatorNotation represents how filter operators are written in parameter keys.
+type OperatorNotation string
+
+const (
+	// OperatorNotationBracket reads operators in brackets: age[ge]=18.
+	OperatorNotationBracket OperatorNotation = ""
+	// OperatorNotationSuffix reads Django-style operator suffixes: age__gte=18.
+	OperatorNotationSuffix OperatorNotation = "suffix"
+	// OperatorNotationBoth accepts both the bracket and the suffix notations.
+	OperatorNotationBoth OperatorNotation = "both"
+)
+
+// PaginationMode represents the query parameters pagination is read from.
+type PaginationMode string
+
+const (
+	// PaginationPage reads page and per_page: page=3&per_page=20.
+	PaginationPage PaginationMode = ""
+	// PaginationOffset reads offset and limit: offset=40&limit=20.
+	PaginationOffset PaginationMode = "offset"
+	// PaginationBoth accepts both the page and the offset parameters.
+	PaginationBoth PaginationMode = "both"
 )
 
+// pages reports whether the mode reads the page and per_page parameters.
+func (m PaginationMode) pages() bool {
+	return m != PaginationOffset
+}
+
+// offsets reports whether the mode reads the offset and limit parameters.
+func (m PaginationMode) offsets() bool {
+	return m == PaginationOffset || m == PaginationBoth
+}
+
+// ParamNames holds the names of the reserved query parameters. Names may be
+// namespaced, e.g. "page[size]". Empty names take their default.
+type ParamNames struct {
+	Page    string // Page number (default "page")
+	PerPage string // Items per page (default "per_page")
+	Offset  string // Items to skip (default "offset")
+	Limit   string // Maximum number of items (default "limit")
+	Sort    string // Sort list (default "sort")
+	Fields  string // Field projection (default "fields" when a projection option is set, disabled otherwise)
+	Cursor  string // Pagination cursor (default "cursor")
+	After   string // Pagination cursor of the next page (default "after")
+	Before  string // Pagination cursor of the previous page (default "before")
+}
+
+// defaultParamNames are the names of the reserved parameters of the native syntax.
+var defaultParamNames = ParamNames{
+	Page:    "page",
+	PerPage: "per_page",
+	Offset:  "offset",
+	Limit:   "limit",
+	Sort:    "sort",
+	Cursor:  "cursor",
+	After:   "after",
+	Before:  "before",
+}
+
+// or returns the names with the unset ones taken from fallback.
+func (n ParamNames) or(fallback ParamNames) ParamNames {
+	return ParamNames{
+		Page:    cmp.Or(n.Page, fallback.Page),
+		PerPage: cmp.Or(n.PerPage, fallback.PerPage),
+		Offset:  cmp.Or(n.Offset, fallback.Offset),
+		Limit:   cmp.Or(n.Limit, fallback.Limit),
+		Sort:    cmp.Or(n.Sort, fallback.Sort),
+		Fields:  cmp.Or(n.Fields, fallback.Fields),
+		Cursor:  cmp.Or(n.Cursor, fallback.Cursor),
+		After:   cmp.Or(n.After, fallback.After),
+		Before:  cmp.Or(n.Before, fallback.Before),
+	}
+}
+
 // Options defines configuration options for parsing and validating query parameters.
 type Options struct {
-	DefaultPerPage int
-	MaxPerPage     int
-	AllowedSorts   []string
-	AllowedFilters []string
+	DefaultPerPage   int                         // Default number of items per page
+	MaxPerPage       int                         // Maximum allowed items per page, also enforced on limit
+	AllowedSorts     []string                    // Allowed fields for sorting (empty = all allowed)
+	AllowedFilters   []string                    // Allowed fields for filtering (empty = all allowed)
+	AllowedIncludes  []string                    // Allowed JSON:API include paths (empty = all allowed)
+	AllowedFields    []string                    // Allowed fields for selection (empty = all allowed)
+	DefaultFields    []string                    // Fields selected when the query selects none (empty = all fields)
+	RequiredFields   []string                    // Fields always selected along with the requested ones, e.g. "id"
+	AllowedOperators map[string][]FilterOperator // Allowed operators per field (unlisted fields = all allowed)
+	FieldMap         map[string]string           // API field name -> storage column or expression (empty = no mapping)
+	FieldTypes       map[string]FieldType        // Value type per field (unlisted fields = untyped strings)
+	EnumValues       map[string][]string         // Accepted values for fields of type FieldTypeEnum
+	MaxDepth         int                         // Maximum nesting depth of filter groups and expressions
+	MaxPatternLength int                         // Maximum length in bytes of a regular expression (re, nre)
+	RSQLParam        string                      // Query parameter carrying an RSQL/FIQL expression (empty = disabled)
+	Syntax           Syntax                      // Query parameter convention (empty = native syntax)
+	OperatorNotation OperatorNotation            // How operators are written in keys (empty = brackets)
+	Pagination       PaginationMode              // Pagination parameters to read (empty = page and per_page)
+	Params           ParamNames                  // Names of the reserved parameters (empty names = defaults)
+	FilterParam      string                      // Prefix filters must be nested in, e.g. "filter" for filter[name][eq] (empty = none)
+	Clock            func() time.Time            // Current time used to resolve relative time values (nil = time.Now)
+	CursorKey        []byte                      // HMAC key signing pagination cursors (empty = cursor pagination disabled)
 }
 
 type OptionFunc func(*Options)
@@ -19,10 +135,12 @@ type OptionFunc func(*Options)
 // NewOptions creates a new Options instance with default values.
 func NewOptions(opts ...OptionFunc) *Options {
 	options := &Options{
-		DefaultPerPage: defaultPerPage,
-		MaxPerPage:     defaultMaxPerPage,
-		AllowedSorts:   []string{},
-		AllowedFilters: []string{},
+		DefaultPerPage:   defaultPerPage,
+		MaxPerPage:       defaultMaxPerPage,
+		MaxDepth:         defaultMaxDepth,
+		MaxPatternLength: defaultMaxPatternLength,
+		AllowedSorts:     []string{},
+		AllowedFilters:   []string{},
 	}
 
 	for _, opt := range opts {
@@ -46,6 +164,85 @@ func WithMaxPerPage(n int) OptionFunc {
 	}
 }
 
+// WithMaxDepth sets the maximum nesting depth of filter groups.
+func WithMaxDepth(n int) OptionFunc {
+	return func(o *Options) {
+		o.MaxDepth = n
+	}
+}
+
+// WithMaxPatternLength sets the maximum length in bytes of the regular
+// expressions of re and nre filters.
+func WithMaxPatternLength(n int) OptionFunc {
+	return func(o *Options) {
+		o.MaxPatternLength = n
+	}
+}
+
+// WithRSQLParam enables RSQL/FIQL filter expressions, read from the given
+// query parameter, e.g. "filter" for "?filter=name==John;age=gt=18".
+func WithRSQLParam(name string) OptionFunc {
+	return func(o *Options) {
+		o.RSQLParam = name
+	}
+}
+
+// WithSyntax sets the query parameter convention queries are parsed with.
+func WithSyntax(syntax Syntax) OptionFunc {
+	return func(o *Options) {
+		o.Syntax = syntax
+	}
+}
+
+// WithOperatorNotation sets how filter operators are written in parameter keys.
+func WithOperatorNotation(notation OperatorNotation) OptionFunc {
+	return func(o *Options) {
+		o.OperatorNotation = notation
+	}
+}
+
+// WithPagination sets the query parameters pagination is read from.
+func WithPagination(mode PaginationMode) OptionFunc {
+	return func(o *Options) {
+		o.Pagination = mode
+	}
+}
+
+// WithParamNames renames the reserved query parameters, e.g. to free a field
+// named "page" or to follow another API style. Unset names keep their default.
+func WithParamNames(names ParamNames) OptionFunc {
+	return func(o *Options) {
+		o.Params = names
+	}
+}
+
+// WithFilterParam requires filters to be nested in the given parameter, e.g.
+// "filter" for "filter[name][eq]=John", so that field names never collide
+// with reserved parameters. Other parameters are then ignored. The name may
+// itself be namespaced, e.g. "q[f]" for "q[f][name]=John".
+func WithFilterParam(name string) OptionFunc {
+	return func(o *Options) {
+		o.FilterParam = name
+	}
+}
+
+// WithClock sets the clock used to resolve relative time values such as
+// "now-7d", e.g. to make parsing deterministic in tests.
+func WithClock(clock func() time.Time) OptionFunc {
+	return func(o *Options) {
+		o.Clock = clock
+	}
+}
+
+// WithCursorKey enables keyset pagination with cursors signed by key, read
+// from the cursor, after and before parameters. The key must be kept secret:
+// anyone holding it can forge cursors.
+func WithCursorKey(key []byte) OptionFunc {
+	return func(o *Options) {
+		o.CursorKey = key
+	}
+}
+
 // WithAllowedSorts sets the allowed sorting fields.
 func WithAllowedSorts(sorts []string) OptionFunc {
 	return func(o *Options) {
@@ -59,3 +256,167 @@ func WithAllowedFilters(filters []string) OptionFunc {
 		o.AllowedFilters = filters
 	}
 }
+
+// WithAllowedFields sets the fields that may be selected with the fields parameter.
+func WithAllowedFields(fields []string) OptionFunc {
+	return func(o *Options) {
+		o.AllowedFields = fields
+	}
+}
+
+// WithDefaultFields sets the fields selected when the query has no fields parameter.
+func WithDefaultFields(fields []string) OptionFunc {
+	return func(o *Options) {
+		o.DefaultFields = fields
+	}
+}
+
+// WithRequiredFields sets the fields always selected when a projection
+// applies, such as the primary key.
+func WithRequiredFields(fields []string) OptionFunc {
+	return func(o *Options) {
+		o.RequiredFields = fields
+	}
+}
+
+// WithAllowedIncludes sets the allowed JSON:API include paths, e.g. "author"
+// and "author.comments".
+func WithAllowedIncludes(includes []string) OptionFunc {
+	return func(o *Options) {
+		o.AllowedIncludes = includes
+	}
+}
+
+// WithAllowedOperators sets the operators each field may be filtered with.
+// Fields missing from the map accept every operator.
+func WithAllowedOperators(operators map[string][]FilterOperator) OptionFunc {
+	return func(o *Options) {
+		o.AllowedOperators = operators
+	}
+}
+
+// WithFieldMap sets the mapping from API field names to storage columns or expressions.
+// When set, fields missing from the map can be neither filtered nor sorted on.
+// A field mapped to an empty column is allowed and keeps its own name.
+func WithFieldMap(fields map[string]string) OptionFunc {
+	return func(o *Options) {
+		o.FieldMap = fields
+	}
+}
+
+// WithFieldTypes sets the value type of each field, merged with the types set
+// before, e.g. by WithEnum. Filter values are converted to the canonical form
+// of their field's type ("007" is "7" for an int), and values that do not
+// parse as the type are dropped, or rejected in strict mode.
+func WithFieldTypes(types map[string]FieldType) OptionFunc {
+	return func(o *Options) {
+		if o.FieldTypes == nil {
+			o.FieldTypes = types
+			return
+		}
+		o.FieldTypes = maps.Clone(o.FieldTypes)
+		maps.Copy(o.FieldTypes, types)
+	}
+}
+
+// WithEnum declares field as an enum accepting only the given values.
+func WithEnum(field string, values ...string) OptionFunc {
+	return func(o *Options) {
+		o.FieldTypes = maps.Clone(o.FieldTypes)
+		if o.FieldTypes == nil {
+			o.FieldTypes = make(map[string]FieldType)
+		}
+		o.FieldTypes[field] = FieldTypeEnum
+
+		o.EnumValues = maps.Clone(o.EnumValues)
+		if o.EnumValues == nil {
+			o.EnumValues = make(map[string][]string)
+		}
+		o.EnumValues[field] = values
+	}
+}
+
+// defaultPerPage returns DefaultPerPage, or the package default when unset.
+func (o Options) defaultPerPage() int {
+	if o.DefaultPerPage <= 0 {
+		return defaultPerPage
+	}
+	return o.DefaultPerPage
+}
+
+// maxPerPage returns MaxPerPage, or the package default when unset.
+func (o Options) maxPerPage() int {
+	if o.MaxPerPage <= 0 {
+		return defaultMaxPerPage
+	}
+	return o.MaxPerPage
+}
+
+// maxDepth returns MaxDepth, or the package default when unset.
+func (o Options) maxDepth() int {
+	if o.MaxDepth <= 0 {
+		return defaultMaxDepth
+	}
+	return o.MaxDepth
+}
+
+// now returns the current time of Clock, or time.Now when unset.
+func (o Options) now() time.Time {
+	if o.Clock == nil {
+		return time.Now()
+	}
+	return o.Clock()
+}
+
+// fieldsParam returns the name of the projection parameter: Params.Fields, or
+// "fields" when AllowedFields, DefaultFields or RequiredFields is set. It is
+// empty when projection is disabled, leaving "fields" an ordinary filter field.
+func (o Options) fieldsParam() string {
+	if o.Params.Fields != "" {
+		return o.Params.Fields
+	}
+	if len(o.AllowedFields) > 0 || len(o.DefaultFields) > 0 || len(o.RequiredFields) > 0 {
+		return "fields"
+	}
+	return ""
+}
+
+// maxPatternLength returns MaxPatternLength, or the package default when unset.
+func (o Options) maxPatternLength() int {
+	if o.MaxPatternLength <= 0 {
+		return defaultMaxPatternLength
+	}
+	return o.MaxPatternLength
+}
+
+// column returns the storage column mapped to an API field name.
+// Reports false when a field map is configured and the field is not part of it.
+func (o Options) column(field string) (string, bool) {
+	if len(o.FieldMap) == 0 {
+		return "", true
+	}
+	column, ok := o.FieldMap[field]
+	return column, ok
+}
+
+// clone returns a copy of the options that shares no slices or maps with o.
+func (o Options) clone() Options {
+	o.AllowedSorts = slices.Clone(o.AllowedSorts)
+	o.CursorKey = slices.Clone(o.CursorKey)
+	o.AllowedFilters = slices.Clone(o.AllowedFilters)
+	o.AllowedIncludes = slices.Clone(o.AllowedIncludes)
+	o.AllowedFields = slices.Clone(o.AllowedFields)
+	o.DefaultFields = slices.Clone(o.DefaultFields)
+	o.RequiredFields = slices.Clone(o.RequiredFields)
+	o.AllowedOperators = maps.Clone(o.AllowedOperators)
+	for field, operators := range o.AllowedOperators {
+		o.AllowedOperators[field] = slices.Clone(operators)
+	}
+	o.FieldMap = maps.Clone(o.FieldMap)
+	o.FieldTypes = maps.Clone(o.FieldTypes)
+	o.EnumValues = maps.Clone(o.EnumValues)
+	for field, values := range o.EnumValues {
+		o.EnumValues[field] = slices.Clone(values)
+	}
+	return o
+}
@@ -3,6 +3,7 @@ package hapi
 import (
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNewOptions(t *testing.T) {
@@ -76,6 +77,66 @@ func TestOptionFunctions(t *testing.T) {
 			check:   func(o *Options) bool { return o.MaxPerPage == 500 },
 			expected: "MaxPerPage should be 500",
 		},
+		{
+			name:     "WithMaxDepth",
+			optFunc:  WithMaxDepth(5),
+			check:    func(o *Options) bool { return o.MaxDepth == 5 },
+			expected: "MaxDepth should be 5",
+		},
+		{
+			name:     "WithMaxPatternLength",
+			optFunc:  WithMaxPatternLength(64),
+			check:    func(o *Options) bool { return o.MaxPatternLength == 64 },
+			expected: "MaxPatternLength should be 64",
+		},
+		{
+			name:     "WithPagination",
+			optFunc:  WithPagination(PaginationBoth),
+			check:    func(o *Options) bool { return o.Pagination == PaginationBoth },
+			expected: "Pagination should be both",
+		},
+		{
+			name:     "WithParamNames",
+			optFunc:  WithParamNames(ParamNames{Page: "page[number]", Sort: "$sort"}),
+			check:    func(o *Options) bool { return o.Params == ParamNames{Page: "page[number]", Sort: "$sort"} },
+			expected: "Params should rename page and sort",
+		},
+		{
+			name:     "WithFilterParam",
+			optFunc:  WithFilterParam("filter"),
+			check:    func(o *Options) bool { return o.FilterParam == "filter" },
+			expected: "FilterParam should be filter",
+		},
+		{
+			name:     "WithClock",
+			optFunc:  WithClock(func() time.Time { return time.Unix(0, 0) }),
+			check:    func(o *Options) bool { return o.Clock != nil && o.Clock().Equal(time.Unix(0, 0)) },
+			expected: "Clock should return the Unix epoch",
+		},
+		{
+			name:     "WithCursorKey",
+			optFunc:  WithCursorKey([]byte("secret")),
+			check:    func(o *Options) bool { return string(o.CursorKey) == "secret" },
+			expected: "CursorKey should be secret",
+		},
+		{
+			name:     "WithRSQLParam",
+			optFunc:  WithRSQLParam("filter"),
+			check:    func(o *Options) bool { return o.RSQLParam == "filter" },
+			expected: "RSQLParam should be filter",
+		},
+		{
+			name:     "WithSyntax",
+			optFunc:  WithSyntax(SyntaxOData),
+			check:    func(o *Options) bool { return o.Syntax == SyntaxOData },
+			expected: "Syntax should be odata",
+		},
+		{
+			name:     "WithOperatorNotation",
+			optFunc:  WithOperatorNotation(OperatorNotationBoth),
+			check:    func(o *Options) bool { return o.OperatorNotation == OperatorNotationBoth },
+			expected: "OperatorNotation should be both",
+		},
 		{
 			name:    "WithAllowedSorts",
 			optFunc: WithAllowedSorts([]string{"id", "name", "date"}),
@@ -92,6 +153,62 @@ func TestOptionFunctions(t *testing.T) {
 			},
 			expected: "AllowedFilters should match",
 		},
+		{
+			name:    "WithAllowedFields",
+			optFunc: WithAllowedFields([]string{"id", "name"}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.AllowedFields, []string{"id", "name"})
+			},
+			expected: "AllowedFields should match",
+		},
+		{
+			name:    "WithDefaultFields",
+			optFunc: WithDefaultFields([]string{"name"}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.DefaultFields, []string{"name"})
+			},
+			expected: "DefaultFields should match",
+		},
+		{
+			name:    "WithRequiredFields",
+			optFunc: WithRequiredFields([]string{"id"}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.RequiredFields, []string{"id"})
+			},
+			expected: "RequiredFields should match",
+		},
+		{
+			name:    "WithAllowedIncludes",
+			optFunc: WithAllowedIncludes([]string{"author", "comments.author"}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.AllowedIncludes, []string{"author", "comments.author"})
+			},
+			expected: "AllowedIncludes should match",
+		},
+		{
+			name:    "WithAllowedOperators",
+			optFunc: WithAllowedOperators(map[string][]FilterOperator{"age": {FilterOperatorGreaterThan}}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.AllowedOperators, map[string][]FilterOperator{"age": {FilterOperatorGreaterThan}})
+			},
+			expected: "AllowedOperators should match",
+		},
+		{
+			name:    "WithFieldTypes",
+			optFunc: WithFieldTypes(map[string]FieldType{"age": FieldTypeInt}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.FieldTypes, map[string]FieldType{"age": FieldTypeInt})
+			},
+			expected: "FieldTypes should match",
+		},
+		{
+			name:    "WithFieldMap",
+			optFunc: WithFieldMap(map[string]string{"created": "u.created_at"}),
+			check: func(o *Options) bool {
+				return reflect.DeepEqual(o.FieldMap, map[string]string{"created": "u.created_at"})
+			},
+			expected: "FieldMap should match",
+		},
 	}
 
 	for _, tt := range tests {
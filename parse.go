@@ -1,11 +1,15 @@
 package hapi
 
 import (
+	"cmp"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // ParseFromRequest parses query parameters from an HTTP request.
@@ -18,7 +22,7 @@ func ParseFromRequest(r *http.Request, opts Options) (Result, error) {
 }
 
 // ParseFromRequestStrict parses query parameters from an HTTP request.
-// Returns an error for any invalid parameters.
+// Returns a ParseErrors listing every invalid parameter.
 func ParseFromRequestStrict(r *http.Request, opts Options) (Result, error) {
 	if r == nil || r.URL == nil {
 		return Result{}, fmt.Errorf("request or URL is nil")
@@ -33,7 +37,7 @@ func Parse(rawURL string, opts Options) (Result, error) {
 }
 
 // ParseStrict parses query parameters from a URL string.
-// Returns an error for any invalid parameters.
+// Returns a ParseErrors listing every invalid parameter.
 func ParseStrict(rawURL string, opts Options) (Result, error) {
 	return parseFromURL(rawURL, opts, true)
 }
@@ -47,55 +51,99 @@ func parseFromURL(rawURL string, opts Options, strict bool) (Result, error) {
 }
 
 func parseQuery(rawQuery string, opts Options, strict bool) (Result, error) {
-	maxPerPage := opts.MaxPerPage
-	if maxPerPage <= 0 {
-		maxPerPage = defaultMaxPerPage
-	}
-	perPage := opts.DefaultPerPage
-	if perPage <= 0 {
-		perPage = defaultPerPage
+	if opts.Clock != nil {
+		// Every relative time of the query is evaluated against the same instant.
+		now := opts.Clock()
+		opts.Clock = func() time.Time { return now }
 	}
 
-	result := Result{
-		PerPage: min(perPage, maxPerPage),
-		Page:    1,
-		Sorts:   make(Sorts, 0),
-		Filters: make(Filters, 0),
+	if opts.Syntax == SyntaxOData {
+		return parseODataQuery(rawQuery, opts, strict)
+	}
+	if opts.Syntax == SyntaxJSONAPI {
+		// JSON:API nests pagination in page[...] and filters in filter[...].
+		opts.Params = opts.Params.or(jsonAPIParamNames)
+		opts.FilterParam = cmp.Or(opts.FilterParam, "filter")
 	}
 
-	for _, filter := range strings.Split(rawQuery, "&") {
-		if filter == "" {
+	maxPerPage := opts.maxPerPage()
+	maxDepth := opts.maxDepth()
+	names := opts.Params.or(defaultParamNames)
+	fieldsParam := opts.fieldsParam()
+	result := newResult(opts)
+	var offset *int
+	var fields Fields
+
+	// root collects every filter, grouped or not, into the boolean expression
+	// exposed as Result.Expr when the query uses filter groups.
+	root := &exprBuilder{operator: LogicalAnd}
+	grouped := false
+	errs := errorCollector{strict: strict}
+
+	// The cursor is decoded once every sort is known, as it must match them.
+	var cursorParam, cursorValue string
+
+	for _, param := range strings.Split(rawQuery, "&") {
+		if param == "" {
 			continue
 		}
 
-		var values Values
+		parts := strings.SplitN(param, "=", 2)
+		// Keys are matched decoded, as encoders such as url.Values.Encode
+		// percent-encode brackets: "page%5Bsize%5D" is "page[size]".
+		key, err := url.QueryUnescape(parts[0])
+		if err != nil {
+			errs.add(param, &ParseError{Code: ErrInvalidEscape, Err: fmt.Errorf("failed to unescape key %q: %w", parts[0], err)})
+			continue
+		}
+		parts[0] = key
 
-		parts := strings.SplitN(filter, "=", 2)
-		if parts[0] == "per_page" {
+		if parts[0] == names.PerPage && opts.Pagination.pages() || parts[0] == names.Limit && opts.Pagination.offsets() {
 			if len(parts) != 2 {
-				if strict {
-					return Result{}, fmt.Errorf("invalid per_page filter format: %s", filter)
-				}
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
 				continue
 			}
 
-			result.PerPage = min(max(1, Value(parts[1]).Int()), maxPerPage)
+			n, err := parseCount(parts[0], parts[1])
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			result.PerPage = min(max(1, n), maxPerPage)
 			continue
-		} else if parts[0] == "page" {
+		} else if parts[0] == names.Page && opts.Pagination.pages() {
 			if len(parts) != 2 {
-				if strict {
-					return Result{}, fmt.Errorf("invalid page filter format: %s", filter)
-				}
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
+				continue
+			}
+
+			n, err := parseCount(parts[0], parts[1])
+			if err != nil {
+				errs.add(param, err)
 				continue
 			}
 
-			result.Page = max(1, Value(parts[1]).Int())
+			result.Page = max(1, n)
 			continue
-		} else if parts[0] == "sort" {
+		} else if parts[0] == names.Offset && opts.Pagination.offsets() {
 			if len(parts) != 2 {
-				if strict {
-					return Result{}, fmt.Errorf("invalid sort filter format: %s", filter)
-				}
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
+				continue
+			}
+
+			n, err := parseCount(parts[0], parts[1])
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			n = max(0, n)
+			offset = &n
+			continue
+		} else if parts[0] == names.Sort {
+			if len(parts) != 2 {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
 				continue
 			}
 
@@ -107,97 +155,429 @@ func parseQuery(rawQuery string, opts Options, strict bool) (Result, error) {
 					continue
 				}
 
-				sort, err := parseSortFromString(sortParam)
+				parseSort := parseSortFromString
+				if opts.Syntax == SyntaxJSONAPI {
+					parseSort = parseJSONAPISort
+				}
+
+				sort, err := parseSort(sortParam)
 				if err != nil {
-					if strict {
-						return Result{}, err
-					}
+					errs.add(param, err)
 					continue
 				}
 
-				if len(opts.AllowedSorts) > 0 && !slices.Contains(opts.AllowedSorts, sort.Field) {
-					if strict {
-						return Result{}, fmt.Errorf("sorting by field %q is not allowed", sort.Field)
-					}
+				sort, err = buildSort(sort, opts)
+				if err != nil {
+					errs.add(param, err)
 					continue
 				}
 
 				result.Sorts = append(result.Sorts, sort)
 			}
 
+			continue
+		} else if len(opts.CursorKey) > 0 && (parts[0] == names.Cursor || parts[0] == names.After || parts[0] == names.Before) {
+			if len(parts) != 2 {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
+				continue
+			}
+			if cursorParam != "" {
+				errs.add(param, &ParseError{Code: ErrInvalidCursor, Err: fmt.Errorf("only one of %s, %s and %s may be set", names.Cursor, names.After, names.Before)})
+				continue
+			}
+
+			cursorParam, cursorValue = parts[0], parts[1]
+			continue
+		} else if fieldsParam != "" && parts[0] == fieldsParam {
+			if len(parts) != 2 {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", parts[0], param)})
+				continue
+			}
+
+			list, err := splitList(parts[1])
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			for _, name := range list {
+				field, err := buildField(name, opts)
+				if err != nil {
+					errs.add(param, err)
+					continue
+				}
+				fields = append(fields, field)
+			}
+			continue
+		} else if opts.Syntax == SyntaxJSONAPI && parts[0] == "include" {
+			if len(parts) != 2 {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid include format: %s", param)})
+				continue
+			}
+
+			includes, err := parseIncludes(parts[1], opts, strict)
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			for _, include := range includes {
+				if !slices.Contains(result.Includes, include) {
+					result.Includes = append(result.Includes, include)
+				}
+			}
+			continue
+		} else if opts.Syntax == SyntaxJSONAPI && strings.HasPrefix(parts[0], "fields[") {
+			if len(parts) != 2 {
+				parts = append(parts, "")
+			}
+
+			resource, fields, err := parseFieldset(parts[0], parts[1])
+			if err != nil {
+				errs.add(param, err)
+				continue
+			}
+
+			if result.Fieldsets == nil {
+				result.Fieldsets = make(map[string][]string)
+			}
+			result.Fieldsets[resource] = fields
 			continue
 		}
 
-		if len(opts.AllowedFilters) > 0 && !slices.Contains(opts.AllowedFilters, parts[0]) {
-			if strict {
-				return Result{}, fmt.Errorf("filtering by field %q is not allowed", parts[0])
+		if opts.RSQLParam != "" && parts[0] == opts.RSQLParam {
+			if len(parts) != 2 {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid %s filter format: %s", opts.RSQLParam, param)})
+				continue
+			}
+
+			expression, err := url.QueryUnescape(parts[1])
+			if err != nil {
+				errs.add(param, &ParseError{Code: ErrInvalidEscape, Value: Value(parts[1]), Err: fmt.Errorf("failed to unescape value %q: %w", parts[1], err)})
+				continue
+			}
+
+			// The expression is dropped as a whole in lenient mode: removing a
+			// single invalid constraint would change the meaning of the others.
+			expr, err := parseRSQL(expression, opts, maxDepth)
+			if err != nil {
+				errs.add(param, err)
+				continue
 			}
 
+			filters, isGrouped := root.addParsed(expr)
+			result.Filters = append(result.Filters, filters...)
+			grouped = grouped || isGrouped
 			continue
 		}
 
 		if len(parts) != 2 {
-			result.Filters = append(result.Filters, Filter{
-				Field:    parts[0],
-				Operator: FilterOperatorEqual,
-				Values:   Values{""},
-			})
+			// A key without a value, e.g. "name" or "deleted_at[isnull]".
+			parts = append(parts, "")
+		}
+
+		if opts.FilterParam != "" {
+			// Only the parameters nested in the filter prefix are filters.
+			rest, ok := strings.CutPrefix(key, opts.FilterParam+"[")
+			if !ok {
+				continue
+			}
+
+			// Unwrap the prefix, which may itself be namespaced:
+			// "filter[name][eq]" and "q[f][name][eq]" are "name[eq]".
+			name, tail, ok := strings.Cut(rest, "]")
+			if !ok {
+				errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid filter format: %s", parts[0])})
+				continue
+			}
+			key = name + tail
+		}
+
+		field, segments, ok := splitKey(key)
+		if !ok {
+			// Malformed operator bracket, e.g. "name[" or "name[gt".
+			errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid operator format: %s", parts[0])})
 			continue
 		}
 
-		field := parts[0]
-		operator := FilterOperatorEqual
-		value := parts[1]
+		var path []groupKey
+		if LogicalOperator(field).Valid() == nil && len(segments) >= 2 {
+			// Filter group, e.g. "or[0][status]" or "or[0][and][1][age][gt]".
+			path = append(path, groupKey{operator: LogicalOperator(field), id: segments[0]})
+			field, segments = segments[1], segments[2:]
+			for len(segments) >= 2 && LogicalOperator(field).Valid() == nil {
+				path = append(path, groupKey{operator: LogicalOperator(field), id: segments[0]})
+				field, segments = segments[1], segments[2:]
+			}
 
-		if open := strings.IndexByte(field, '['); open >= 0 {
-			if !strings.HasSuffix(field, "]") {
-				// Malformed operator bracket, e.g. "name[" or "name[gt".
-				if strict {
-					return Result{}, fmt.Errorf("invalid operator format: %s", field)
-				}
+			if len(path) > maxDepth {
+				errs.add(param, &ParseError{Code: ErrMaxDepthExceeded, Field: field, Err: fmt.Errorf("filter group nesting of %s exceeds the maximum depth of %d", parts[0], maxDepth)})
+				root.drop(path)
 				continue
 			}
-			operator = FilterOperator(field[open+1 : len(field)-1])
-			field = field[:open]
+		}
+
+		if field == "" {
+			// A filter without field name, e.g. "filter[]" or "or[0][]".
+			errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("missing field name: %s", parts[0])})
+			root.drop(path)
+			continue
+		}
+
+		if len(segments) > 1 || len(segments) == 1 && opts.OperatorNotation == OperatorNotationSuffix {
+			errs.add(param, &ParseError{Code: ErrInvalidFormat, Err: fmt.Errorf("invalid operator format: %s", parts[0])})
+			root.drop(path)
+			continue
+		}
+
+		operator, fold := FilterOperatorEqual, false
+		if len(segments) == 1 {
+			operator = FilterOperator(segments[0])
+		} else if opts.OperatorNotation != OperatorNotationBracket {
+			field, operator, fold = splitSuffix(field, opts)
 		}
 
 		if err := operator.Valid(); err != nil {
+			errs.add(param, withField(err, field))
+			root.drop(path)
+			continue
+		}
+
+		values, err := parseValues(operator, parts[1], strict)
+		if err != nil {
+			errs.add(param, err)
+			root.drop(path)
+			continue
+		}
+		if fold {
+			operator, values = foldLookup(operator, values)
+		}
+
+		filter, err := buildFilter(field, operator, values, opts)
+		if err != nil {
+			errs.add(param, err)
+			root.drop(path)
+			continue
+		}
+		filter.Escaped = fold
+
+		if len(path) == 0 {
+			result.Filters = append(result.Filters, filter)
+		} else {
+			grouped = true
+		}
+		root.add(path, filter)
+	}
+
+	if cursorParam != "" {
+		param := cursorParam + "=" + cursorValue
+		if token, err := url.QueryUnescape(cursorValue); err != nil {
+			errs.add(param, &ParseError{Code: ErrInvalidEscape, Value: Value(cursorValue), Err: fmt.Errorf("failed to unescape value %q: %w", cursorValue, err)})
+		} else if cursor, err := parseCursor(token, result.Sorts, opts.CursorKey); err != nil {
+			errs.add(param, err)
+		} else {
+			// after and before force the direction of the cursor.
+			switch cursorParam {
+			case names.After:
+				cursor.Before = false
+			case names.Before:
+				cursor.Before = true
+			}
+			result.Cursor = cursor
+		}
+	}
+
+	if err := errs.err(); err != nil {
+		return Result{}, err
+	}
+
+	result.Fields = projection(fields, opts)
+
+	// An offset, even zero, overrides the page, which is derived from it.
+	if offset != nil {
+		result.offset = offset
+		result.Page = *offset/result.PerPage + 1
+	}
+
+	// Filters stay flat when every group was dropped in lenient mode.
+	if expr := root.build(); grouped && !allLeaves(expr.Children) {
+		result.Expr = &expr
+	}
+
+	return result, nil
+}
+
+// splitKey splits a parameter key such as "or[0][age][gt]" into its head
+// ("or") and its bracketed segments ("0", "age", "gt").
+// Reports false if the brackets are malformed.
+func splitKey(key string) (string, []string, bool) {
+	open := strings.IndexByte(key, '[')
+	if open < 0 {
+		return key, nil, true
+	}
+
+	var segments []string
+	for rest := key[open:]; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return "", nil, false
+		}
+		segments = append(segments, rest[1:end])
+		rest = rest[end+1:]
+	}
+
+	return key[:open], segments, true
+}
+
+// parseValues unescapes the raw value of a filter, splitting it on commas for
+// list operators. In lenient mode, list items that fail to unescape are skipped
+// instead of failing the whole filter.
+func parseValues(operator FilterOperator, value string, strict bool) (Values, error) {
+	if !operator.IsList() {
+		unescaped, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, &ParseError{Code: ErrInvalidEscape, Operator: operator, Value: Value(value), Err: fmt.Errorf("failed to unescape value %q: %w", value, err)}
+		}
+		return Values{Value(unescaped)}, nil
+	}
+
+	var values Values
+	for _, v := range strings.Split(value, ",") {
+		unescaped, err := url.QueryUnescape(v)
+		if err != nil {
 			if strict {
-				return Result{}, err
+				return nil, &ParseError{Code: ErrInvalidEscape, Operator: operator, Value: Value(v), Err: fmt.Errorf("failed to unescape value %q: %w", v, err)}
 			}
 			continue
 		}
 
-		if operator.IsList() {
-			for _, v := range strings.Split(value, ",") {
-				unescaped, err := url.QueryUnescape(v)
-				if err != nil {
-					if strict {
-						return Result{}, fmt.Errorf("failed to unescape value %q: %w", v, err)
-					}
-					continue
-				}
+		values = append(values, Value(unescaped))
+	}
+	return values, nil
+}
 
-				values = append(values, Value(unescaped))
-			}
-		} else {
-			unescaped, err := url.QueryUnescape(value)
-			if err != nil {
-				if strict {
-					return Result{}, fmt.Errorf("failed to unescape value %q: %w", value, err)
-				}
-				continue
+// newResult returns a Result holding the default pagination of opts.
+func newResult(opts Options) Result {
+	result := Result{
+		PerPage: min(opts.defaultPerPage(), opts.maxPerPage()),
+		Page:    1,
+		Sorts:   make(Sorts, 0),
+		Filters: make(Filters, 0),
+	}
+	if opts.Clock != nil {
+		result.Now = opts.Clock()
+	}
+	return result
+}
+
+// parseCount parses the integer value of a pagination parameter.
+func parseCount(name, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, &ParseError{Code: ErrInvalidValue, Value: Value(value), Err: fmt.Errorf("invalid %s value: %q", name, value)}
+	}
+	return n, nil
+}
+
+// nullCheck resolves the operator of a null check from its optional boolean
+// value: "deleted_at[isnull]=false" is the same as "deleted_at[notnull]".
+func nullCheck(operator FilterOperator, values Values) (FilterOperator, error) {
+	if len(values) > 1 {
+		return "", fmt.Errorf("operator %q expects at most one value, got %d", operator, len(values))
+	}
+	if len(values) == 0 || values[0] == "" {
+		return operator, nil
+	}
+
+	b, err := strconv.ParseBool(string(values[0]))
+	if err != nil {
+		return "", fmt.Errorf("operator %q expects a boolean value, got %q", operator, values[0])
+	}
+	if b {
+		return operator, nil
+	}
+	if operator == FilterOperatorIsNull {
+		return FilterOperatorNotNull, nil
+	}
+	return FilterOperatorIsNull, nil
+}
+
+// buildSort validates a parsed sort against the options and resolves the
+// storage column of its field.
+func buildSort(sort Sort, opts Options) (Sort, error) {
+	column, ok := opts.column(sort.Field)
+	if !ok || len(opts.AllowedSorts) > 0 && !slices.Contains(opts.AllowedSorts, sort.Field) {
+		return Sort{}, &ParseError{Code: ErrSortNotAllowed, Field: sort.Field, Err: fmt.Errorf("sorting by field %q is not allowed", sort.Field)}
+	}
+
+	sort.Column = column
+	return sort, nil
+}
+
+// buildFilter validates a parsed filter against the options and resolves
+// the storage column of its field. Null checks are resolved from their
+// optional boolean value and returned without values, and the regular
+// expressions of re and nre filters are compiled.
+func buildFilter(field string, operator FilterOperator, values Values, opts Options) (Filter, error) {
+	column, ok := opts.column(field)
+	if !ok || len(opts.AllowedFilters) > 0 && !slices.Contains(opts.AllowedFilters, field) {
+		return Filter{}, &ParseError{Code: ErrFieldNotAllowed, Field: field, Operator: operator, Err: fmt.Errorf("filtering by field %q is not allowed", field)}
+	}
+
+	if operator.IsNullCheck() {
+		resolved, err := nullCheck(operator, values)
+		if err != nil {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Value: values.First(), Err: fmt.Errorf("invalid value for field %q: %w", field, err)}
+		}
+		operator, values = resolved, nil
+	}
+
+	if operators, ok := opts.AllowedOperators[field]; ok && !slices.Contains(operators, operator) {
+		return Filter{}, &ParseError{Code: ErrOperatorNotAllowed, Field: field, Operator: operator, Err: fmt.Errorf("operator %q is not allowed for field %q", operator, field)}
+	}
+
+	// Patterns are matched against the textual form of the field and are not typed values.
+	if fieldType, ok := opts.FieldTypes[field]; ok && !operator.IsPattern() && len(values) > 0 {
+		now := opts.now()
+		normalized := make(Values, len(values))
+		for i, value := range values {
+			var err error
+			if normalized[i], err = fieldType.normalize(value, opts.EnumValues[field], now); err != nil {
+				return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Value: value, Err: fmt.Errorf("invalid value for field %q: %w", field, err)}
 			}
+		}
+		values = normalized
+	}
+
+	var re *regexp.Regexp
+	if operator.IsRegexp() {
+		if len(values) != 1 {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Err: fmt.Errorf("operator %q expects exactly 1 value, got %d", operator, len(values))}
+		}
+		if maxLength := opts.maxPatternLength(); len(values[0]) > maxLength {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Value: values[0], Err: fmt.Errorf("regular expression for field %q exceeds the maximum length of %d", field, maxLength)}
+		}
 
-			values = append(values, Value(unescaped))
+		var err error
+		if re, err = regexp.Compile(string(values[0])); err != nil {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Value: values[0], Err: fmt.Errorf("invalid regular expression for field %q: %w", field, err)}
 		}
+	}
 
-		result.Filters = append(result.Filters, Filter{
-			Field:    field,
-			Operator: operator,
-			Values:   values,
-		})
+	if operator.IsRange() {
+		if len(values) != 2 {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Err: fmt.Errorf("operator %q expects exactly 2 values, got %d", operator, len(values))}
+		}
+		if c, ok := opts.FieldTypes[field].compare(values[0], values[1]); ok && c > 0 {
+			return Filter{}, &ParseError{Code: ErrInvalidValue, Field: field, Operator: operator, Value: values[0], Err: fmt.Errorf("invalid range for field %q: lower bound %q is greater than upper bound %q", field, values[0], values[1])}
+		}
 	}
 
-	return result, nil
+	return Filter{
+		Field:    field,
+		Column:   column,
+		Operator: operator,
+		Values:   values,
+		Regexp:   re,
+	}, nil
 }
@@ -0,0 +1,41 @@
+package hapi
+
+import "testing"
+
+func TestParse_AllowedOperators(t *testing.T) {
+	opts := Options{AllowedOperators: map[string][]FilterOperator{
+		"status": {FilterOperatorEqual, FilterOperatorNotEqual, FilterOperatorIn, FilterOperatorNotIn},
+		"age":    {FilterOperatorGreaterThan, FilterOperatorGreaterOrEqual, FilterOperatorLessThan, FilterOperatorLessOrEqual},
+	}}
+
+	r, err := ParseStrict("http://x/users?status[in]=a,b&age[ge]=18&name[lk]=Jo%25", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Filters) != 3 {
+		t.Errorf("Filters = %v, want 3 filters", r.Filters)
+	}
+
+	for _, u := range []string{
+		"http://x/users?status[lk]=%25",
+		"http://x/users?age=18",
+		"http://x/users?age[inlk]=1,2",
+	} {
+		_, err := ParseStrict(u, opts)
+		if err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+			continue
+		}
+		if !contains(err.Error(), "is not allowed for field") {
+			t.Errorf("ParseStrict(%q) error = %v, want operator rejection", u, err)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept rejected filter: %v", u, r.Filters)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_ArrayOperators(t *testing.T) {
+	r, err := ParseStrict("http://x/posts?tags[all]=go,api&tags[in]=go,api&tags[ov]=rust,zig&roles[cb]=admin,user", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Filters{
+		{Field: "tags", Operator: FilterOperatorContainsAll, Values: Values{"go", "api"}},
+		{Field: "tags", Operator: FilterOperatorIn, Values: Values{"go", "api"}},
+		{Field: "tags", Operator: FilterOperatorOverlaps, Values: Values{"rust", "zig"}},
+		{Field: "roles", Operator: FilterOperatorContainedBy, Values: Values{"admin", "user"}},
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %#v, want %#v", r.Filters, want)
+	}
+}
+
+// Every element of an array operator is validated against the field type.
+func TestParse_ArrayOperatorsFieldTypes(t *testing.T) {
+	opts := Options{FieldTypes: map[string]FieldType{"ids": FieldTypeInt}}
+
+	if _, err := ParseStrict("http://x/posts?ids[all]=1,2", opts); err != nil {
+		t.Errorf("ParseStrict() error = %v", err)
+	}
+	if _, err := ParseStrict("http://x/posts?ids[ov]=1,two", opts); err == nil {
+		t.Error("ParseStrict() expected error for invalid element, got nil")
+	}
+}
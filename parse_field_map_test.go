@@ -0,0 +1,79 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_FieldMap(t *testing.T) {
+	opts := Options{FieldMap: map[string]string{
+		"created": "u.created_at",
+		"name":    "u.name",
+	}}
+
+	r, err := ParseStrict("http://x/users?created[gt]=2024-01-01&name=John&sort=created:desc", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilters := Filters{
+		{Field: "created", Column: "u.created_at", Operator: FilterOperatorGreaterThan, Values: Values{"2024-01-01"}},
+		{Field: "name", Column: "u.name", Operator: FilterOperatorEqual, Values: Values{"John"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %#v, want %#v", r.Filters, wantFilters)
+	}
+
+	wantSorts := Sorts{{Field: "created", Column: "u.created_at", Direction: SortDirectionDesc}}
+	if !reflect.DeepEqual(r.Sorts, wantSorts) {
+		t.Errorf("Sorts = %#v, want %#v", r.Sorts, wantSorts)
+	}
+
+	// Lookups keep working with the API field name.
+	if got := r.Filters.GetFirstFromField("created").ColumnName(); got != "u.created_at" {
+		t.Errorf("ColumnName() = %q, want %q", got, "u.created_at")
+	}
+}
+
+func TestParse_FieldMapRejectsUnmappedFields(t *testing.T) {
+	opts := Options{FieldMap: map[string]string{"name": "u.name"}}
+
+	for _, u := range []string{
+		"http://x/users?password_hash=x",
+		"http://x/users?password_hash[lk]=%25",
+		"http://x/users?sort=password_hash:asc",
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error for unmapped field, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 || len(r.Sorts) != 0 {
+			t.Errorf("Parse(%q) kept unmapped field: filters=%v sorts=%v", u, r.Filters, r.Sorts)
+		}
+	}
+}
+
+// AllowedFilters must be checked against the field name, not the raw
+// "field[op]" parameter key.
+func TestParse_AllowedFiltersWithOperator(t *testing.T) {
+	r, err := ParseStrict("http://x/users?age[gt]=18", Options{AllowedFilters: []string{"age"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Filters) != 1 || r.Filters[0].Field != "age" {
+		t.Errorf("Filters = %#v, want a single age filter", r.Filters)
+	}
+}
+
+func TestFilterColumnName(t *testing.T) {
+	if got := (Filter{Field: "name"}).ColumnName(); got != "name" {
+		t.Errorf("ColumnName() = %q, want %q", got, "name")
+	}
+	if got := (Sort{Field: "name", Column: "u.name"}).ColumnName(); got != "u.name" {
+		t.Errorf("ColumnName() = %q, want %q", got, "u.name")
+	}
+}
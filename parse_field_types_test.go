@@ -0,0 +1,133 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse_FieldTypes(t *testing.T) {
+	opts := *NewOptions(
+		WithFieldTypes(map[string]FieldType{
+			"age":  FieldTypeInt,
+			"id":   FieldTypeUUID,
+			"name": FieldTypeString,
+		}),
+		WithEnum("status", "active", "pending"),
+	)
+
+	r, err := ParseStrict("http://x/users?age[gt]=18&status[in]=active,pending&name[lk]=Jo%25&id=123e4567-e89b-12d3-a456-426614174000", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Filters) != 4 {
+		t.Errorf("Filters = %v, want 4 filters", r.Filters)
+	}
+
+	for _, u := range []string{
+		"http://x/users?age[gt]=abc",
+		"http://x/users?age",
+		"http://x/users?status[in]=active,banned",
+		"http://x/users?id=42",
+	} {
+		_, err := ParseStrict(u, opts)
+		if err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept invalid filter: %v", u, r.Filters)
+		}
+	}
+}
+
+// Pattern operators carry LIKE patterns, not typed values.
+func TestParse_FieldTypesSkipPatterns(t *testing.T) {
+	opts := Options{FieldTypes: map[string]FieldType{"id": FieldTypeUUID}}
+
+	r, err := ParseStrict("http://x/users?id[lk]=123e%25", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Filters{{Field: "id", Operator: FilterOperatorLike, Values: Values{"123e%"}}}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %#v, want %#v", r.Filters, want)
+	}
+}
+
+func TestParse_FieldTypesErrorMessage(t *testing.T) {
+	_, err := ParseStrict("http://x/users?age[gt]=abc", Options{FieldTypes: map[string]FieldType{"age": FieldTypeInt}})
+	want := `invalid value for field "age": expected int, got "abc"`
+	if err == nil || err.Error() != want {
+		t.Errorf("ParseStrict() error = %v, want %q", err, want)
+	}
+}
+
+func TestWithEnumDoesNotMutateSharedMaps(t *testing.T) {
+	types := map[string]FieldType{"age": FieldTypeInt}
+	opts := NewOptions(WithFieldTypes(types), WithEnum("status", "a", "b"))
+
+	if _, ok := types["status"]; ok {
+		t.Error("WithEnum mutated the map passed to WithFieldTypes")
+	}
+	if opts.FieldTypes["status"] != FieldTypeEnum || opts.FieldTypes["age"] != FieldTypeInt {
+		t.Errorf("FieldTypes = %v, want age:int and status:enum", opts.FieldTypes)
+	}
+	if !reflect.DeepEqual(opts.EnumValues["status"], []string{"a", "b"}) {
+		t.Errorf("EnumValues = %v, want status:[a b]", opts.EnumValues)
+	}
+}
+
+func TestWithFieldTypesKeepsEnum(t *testing.T) {
+	types := map[string]FieldType{"age": FieldTypeInt}
+	opts := NewOptions(WithEnum("status", "a", "b"), WithFieldTypes(types))
+
+	if opts.FieldTypes["status"] != FieldTypeEnum || opts.FieldTypes["age"] != FieldTypeInt {
+		t.Errorf("FieldTypes = %v, want age:int and status:enum", opts.FieldTypes)
+	}
+	if _, ok := types["status"]; ok {
+		t.Error("WithFieldTypes mutated the map passed to it")
+	}
+}
+
+func TestParse_FieldTypesNormalize(t *testing.T) {
+	opts := Options{FieldTypes: map[string]FieldType{
+		"age":    FieldTypeInt,
+		"size":   FieldTypeInt64,
+		"score":  FieldTypeFloat,
+		"active": FieldTypeBool,
+		"id":     FieldTypeUUID,
+	}}
+
+	r, err := ParseStrict("http://x/users?age=007&size=%2B42&score=1.50&active=TRUE&id=6BA7B810-9DAD-11D1-80B4-00C04FD430C8", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]Value{
+		"age":    "7",
+		"size":   "42",
+		"score":  "1.5",
+		"active": "true",
+		"id":     "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+	}
+	for field, value := range want {
+		if got := r.Filters.GetFirstFromField(field).Values.First(); got != value {
+			t.Errorf("%s = %q, want %q", field, got, value)
+		}
+	}
+}
+
+func TestParse_FieldTypesRejectNonFinite(t *testing.T) {
+	opts := Options{FieldTypes: map[string]FieldType{"price": FieldTypeFloat}}
+
+	for _, query := range []string{"price[gt]=NaN", "price=Inf", "price[lt]=-infinity"} {
+		if _, err := ParseStrict("http://x/items?"+query, opts); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("ParseStrict(%q) error = %v, want %v", query, err, ErrInvalidValue)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse_Fields(t *testing.T) {
+	opts := Options{
+		AllowedFields:  []string{"id", "name", "email", "created"},
+		DefaultFields:  []string{"name", "email"},
+		RequiredFields: []string{"id"},
+		FieldMap:       map[string]string{"id": "u.id", "name": "u.name", "email": "u.email", "created": "u.created_at"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  Fields
+	}{
+		{"requested fields", "fields=name,created", Fields{{"id", "u.id"}, {"name", "u.name"}, {"created", "u.created_at"}}},
+		{"required field requested", "fields=name,id", Fields{{"id", "u.id"}, {"name", "u.name"}}},
+		{"repeated parameter", "fields=name&fields=email", Fields{{"id", "u.id"}, {"name", "u.name"}, {"email", "u.email"}}},
+		{"default projection", "", Fields{{"id", "u.id"}, {"name", "u.name"}, {"email", "u.email"}}},
+		{"empty list", "fields=", Fields{{"id", "u.id"}, {"name", "u.name"}, {"email", "u.email"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseStrict("http://x/users?"+tt.query, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.Fields, tt.want) {
+				t.Errorf("Fields = %v, want %v", r.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_FieldsWithoutProjection(t *testing.T) {
+	r, err := ParseStrict("http://x/users?name=John", Options{RequiredFields: []string{"id"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Fields != nil {
+		t.Errorf("Fields = %v, want nil to select every field", r.Fields)
+	}
+
+	// Without projection options, fields is an ordinary filter field.
+	r, err = ParseStrict("http://x/users?fields=abc", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Fields != nil || r.Filters.GetFirstFromField("fields").Values.First() != "abc" {
+		t.Errorf("Fields = %v, Filters = %v, want a fields filter", r.Fields, r.Filters)
+	}
+
+	// Naming the parameter enables the projection.
+	r, err = ParseStrict("http://x/users?fields=name", Options{Params: ParamNames{Fields: "fields"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Fields{{Name: "name"}}); !reflect.DeepEqual(r.Fields, want) || len(r.Filters) != 0 {
+		t.Errorf("Fields = %v, want %v", r.Fields, want)
+	}
+}
+
+func TestParse_FieldsValidation(t *testing.T) {
+	opts := Options{AllowedFields: []string{"id", "name"}, RequiredFields: []string{"id"}}
+
+	_, err := ParseStrict("http://x/users?fields=name,password,salary", opts)
+	var parseErrs ParseErrors
+	if !errors.As(err, &parseErrs) || len(parseErrs) != 2 || !errors.Is(err, ErrSelectNotAllowed) {
+		t.Fatalf("ParseStrict() error = %v, want two %v errors", err, ErrSelectNotAllowed)
+	}
+	if parseErrs[0].Field != "password" || parseErrs[1].Field != "salary" {
+		t.Errorf("rejected fields = %s, %s, want password, salary", parseErrs[0].Field, parseErrs[1].Field)
+	}
+
+	r, err := Parse("http://x/users?fields=name,password", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Fields.Names(); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Errorf("Fields = %v, want [id name]", got)
+	}
+
+	if _, err := ParseStrict("http://x/users?fields", opts); err == nil {
+		t.Error("ParseStrict() expected error for fields without value, got nil")
+	}
+}
+
+func TestFieldsHelpers(t *testing.T) {
+	fields := Fields{{Name: "id"}, {Name: "created", Column: "u.created_at"}}
+
+	if !fields.Has("created") || fields.Has("name") {
+		t.Error("Has() reported wrong membership")
+	}
+	if got := fields[1].ColumnName(); got != "u.created_at" {
+		t.Errorf("ColumnName() = %q, want u.created_at", got)
+	}
+	if got := fields[0].ColumnName(); got != "id" {
+		t.Errorf("ColumnName() = %q, want id", got)
+	}
+}
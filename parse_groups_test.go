@@ -0,0 +1,165 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_FilterGroups(t *testing.T) {
+	r, err := ParseStrict("http://x/users?age[ge]=18&or[0][status]=active&or[0][owner]=me&or[1][role][in]=admin,editor&or[1][and][0][team]=core&or[1][and][0][lead]=true", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	age := Filter{Field: "age", Operator: FilterOperatorGreaterOrEqual, Values: Values{"18"}}
+	if !reflect.DeepEqual(r.Filters, Filters{age}) {
+		t.Errorf("Filters = %v, want only the ungrouped age filter", r.Filters)
+	}
+
+	want := And(
+		FilterExpr(age),
+		Or(
+			FilterExpr(Filter{Field: "status", Operator: FilterOperatorEqual, Values: Values{"active"}}),
+			FilterExpr(Filter{Field: "owner", Operator: FilterOperatorEqual, Values: Values{"me"}}),
+		),
+		Or(
+			FilterExpr(Filter{Field: "role", Operator: FilterOperatorIn, Values: Values{"admin", "editor"}}),
+			And(
+				FilterExpr(Filter{Field: "team", Operator: FilterOperatorEqual, Values: Values{"core"}}),
+				FilterExpr(Filter{Field: "lead", Operator: FilterOperatorEqual, Values: Values{"true"}}),
+			),
+		),
+	)
+	if r.Expr == nil {
+		t.Fatal("Expr = nil, want expression")
+	}
+	if !reflect.DeepEqual(*r.Expr, want) {
+		t.Errorf("Expr = %v, want %v", r.Expr, want)
+	}
+	if !reflect.DeepEqual(r.Where(), want) {
+		t.Errorf("Where() = %v, want %v", r.Where(), want)
+	}
+}
+
+func TestParse_FilterGroupsNot(t *testing.T) {
+	r, err := ParseStrict("http://x/users?not[0][status]=banned", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := And(Not(FilterExpr(Filter{Field: "status", Operator: FilterOperatorEqual, Values: Values{"banned"}})))
+	if r.Expr == nil || !reflect.DeepEqual(*r.Expr, want) {
+		t.Errorf("Expr = %v, want %v", r.Expr, want)
+	}
+}
+
+// Without groups, Expr stays nil and Where() derives the conjunction of Filters.
+func TestParse_NoGroupsKeepsFlatFilters(t *testing.T) {
+	r, err := ParseStrict("http://x/users?name=John&or[eq]=x", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Expr != nil {
+		t.Errorf("Expr = %v, want nil", r.Expr)
+	}
+
+	want := And(
+		FilterExpr(Filter{Field: "name", Operator: FilterOperatorEqual, Values: Values{"John"}}),
+		FilterExpr(Filter{Field: "or", Operator: FilterOperatorEqual, Values: Values{"x"}}),
+	)
+	if !reflect.DeepEqual(r.Where(), want) {
+		t.Errorf("Where() = %v, want %v", r.Where(), want)
+	}
+}
+
+func TestParse_FilterGroupsValidation(t *testing.T) {
+	opts := Options{
+		AllowedFilters: []string{"status", "a"},
+		MaxDepth:       2,
+	}
+
+	for _, u := range []string{
+		"http://x/users?or[0][salary]=1",
+		"http://x/users?or[0][status][xx]=1",
+		"http://x/users?or[0][status][eq][ne]=1",
+		"http://x/users?or[0][and][0][or][0][a]=1",
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if r.Expr != nil || len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept invalid group filter: %v", u, r.Expr)
+		}
+	}
+
+	if _, err := ParseStrict("http://x/users?or[0][and][0][a]=1", opts); err != nil {
+		t.Errorf("ParseStrict() at maximum depth: %v", err)
+	}
+}
+
+// In lenient mode, a group with a rejected member is dropped as a whole:
+// keeping its other members would change its meaning.
+func TestParse_FilterGroupsDropRejected(t *testing.T) {
+	opts := Options{AllowedFilters: []string{"role", "age"}}
+	leaf := func(field string, op FilterOperator, v Value) Expr {
+		return FilterExpr(Filter{Field: field, Operator: op, Values: Values{v}})
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  *Expr
+	}{
+		{"not group", "not[0][role]=admin&not[0][secret]=x", nil},
+		{"rejected member first", "or[0][secret]=x&or[0][role]=admin", nil},
+		{"nested group", "age[ge]=18&or[0][role]=admin&or[1][and][0][role]=dev&or[1][and][0][secret]=x", &Expr{Operator: LogicalAnd, Children: []Expr{
+			leaf("age", FilterOperatorGreaterOrEqual, "18"),
+			{Operator: LogicalOr, Children: []Expr{leaf("role", FilterOperatorEqual, "admin")}},
+		}}},
+		{"only flat filters left", "age[ge]=18&or[0][role]=admin&or[0][secret]=x", nil},
+		{"other groups kept", "not[0][role]=admin&not[0][secret]=x&not[1][role]=guest", &Expr{Operator: LogicalAnd, Children: []Expr{
+			Not(leaf("role", FilterOperatorEqual, "guest")),
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse("http://x/users?"+tt.query, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.Expr, tt.want) {
+				t.Errorf("Expr = %v, want %v", r.Expr, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		head     string
+		segments []string
+		ok       bool
+	}{
+		{"name", "name", nil, true},
+		{"name[gt]", "name", []string{"gt"}, true},
+		{"or[0][age][gt]", "or", []string{"0", "age", "gt"}, true},
+		{"name[]", "name", []string{""}, true},
+		{"name[", "", nil, false},
+		{"name[gt", "", nil, false},
+		{"name[gt]x", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		head, segments, ok := splitKey(tt.key)
+		if head != tt.head || !reflect.DeepEqual(segments, tt.segments) || ok != tt.ok {
+			t.Errorf("splitKey(%q) = %q, %q, %t, want %q, %q, %t", tt.key, head, segments, ok, tt.head, tt.segments, tt.ok)
+		}
+	}
+}
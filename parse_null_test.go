@@ -0,0 +1,91 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_NullChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  Options
+		want  Filters
+	}{
+		{
+			name:  "Value-less null checks",
+			query: "deleted_at[isnull]&archived_at[notnull]",
+			want: Filters{
+				{Field: "deleted_at", Operator: FilterOperatorIsNull},
+				{Field: "archived_at", Operator: FilterOperatorNotNull},
+			},
+		},
+		{
+			name:  "Boolean-valued null checks",
+			query: "a[isnull]=true&b[isnull]=false&c[notnull]=0&d[isnull]=",
+			want: Filters{
+				{Field: "a", Operator: FilterOperatorIsNull},
+				{Field: "b", Operator: FilterOperatorNotNull},
+				{Field: "c", Operator: FilterOperatorIsNull},
+				{Field: "d", Operator: FilterOperatorIsNull},
+			},
+		},
+		{
+			name:  "Empty string is not null",
+			query: "deleted_at=&name",
+			want: Filters{
+				{Field: "deleted_at", Operator: FilterOperatorEqual, Values: Values{""}},
+				{Field: "name", Operator: FilterOperatorEqual, Values: Values{""}},
+			},
+		},
+		{
+			name:  "Typed fields are not validated",
+			query: "age[isnull]",
+			opts:  Options{FieldTypes: map[string]FieldType{"age": FieldTypeInt}},
+			want:  Filters{{Field: "age", Operator: FilterOperatorIsNull}},
+		},
+		{
+			name:  "Django suffix",
+			query: "deleted_at__isnull=false",
+			opts:  Options{OperatorNotation: OperatorNotationSuffix},
+			want:  Filters{{Field: "deleted_at", Operator: FilterOperatorNotNull}},
+		},
+		{
+			name:  "Allowed operators apply to the resolved operator",
+			query: "deleted_at[notnull]=false",
+			opts:  Options{AllowedOperators: map[string][]FilterOperator{"deleted_at": {FilterOperatorIsNull}}},
+			want:  Filters{{Field: "deleted_at", Operator: FilterOperatorIsNull}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseStrict("http://x/users?"+tt.query, tt.opts)
+			if err != nil {
+				t.Fatalf("ParseStrict() error = %v", err)
+			}
+			if !reflect.DeepEqual(r.Filters, tt.want) {
+				t.Errorf("Filters = %#v, want %#v", r.Filters, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_NullCheckErrors(t *testing.T) {
+	for _, u := range []string{
+		"http://x/users?deleted_at[isnull]=maybe",
+		"http://x/users?deleted_at[notnull]=yes",
+	} {
+		if _, err := ParseStrict(u, Options{}); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, Options{})
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept invalid filter: %v", u, r.Filters)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package hapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse_PaginationModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       PaginationMode
+		query      string
+		wantLimit  int
+		wantOffset int
+		wantPage   int
+		wantFilter string // Field of the only filter, empty when none
+	}{
+		{"page mode", PaginationPage, "page=3&per_page=20", 20, 40, 3, ""},
+		{"page mode ignores offset", PaginationPage, "offset=40", defaultPerPage, 0, 1, "offset"},
+		{"offset mode", PaginationOffset, "offset=40&limit=20", 20, 40, 3, ""},
+		{"offset mode ignores page", PaginationOffset, "page=3&limit=20", 20, 0, 1, "page"},
+		{"offset not aligned on a page", PaginationOffset, "offset=15&limit=10", 10, 15, 2, ""},
+		{"limit capped to MaxPerPage", PaginationOffset, "limit=500", 50, 0, 1, ""},
+		{"limit below one", PaginationOffset, "limit=0", 1, 0, 1, ""},
+		{"negative offset", PaginationOffset, "offset=-5", defaultPerPage, 0, 1, ""},
+		{"both with pages", PaginationBoth, "page=2&per_page=5", 5, 5, 2, ""},
+		{"both with offsets", PaginationBoth, "offset=10&limit=5", 5, 10, 3, ""},
+		{"offset overrides page", PaginationBoth, "page=9&offset=10&per_page=5", 5, 10, 3, ""},
+		{"zero offset overrides page", PaginationBoth, "page=9&offset=0&per_page=5", 5, 0, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseStrict("http://x/users?"+tt.query, Options{MaxPerPage: 50, Pagination: tt.mode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r.Limit() != tt.wantLimit || r.Offset() != tt.wantOffset || r.Page != tt.wantPage {
+				t.Errorf("Limit/Offset/Page = %d/%d/%d, want %d/%d/%d", r.Limit(), r.Offset(), r.Page, tt.wantLimit, tt.wantOffset, tt.wantPage)
+			}
+
+			switch {
+			case tt.wantFilter == "" && len(r.Filters) != 0:
+				t.Errorf("Filters = %v, want none", r.Filters)
+			case tt.wantFilter != "" && (len(r.Filters) != 1 || r.Filters[0].Field != tt.wantFilter):
+				t.Errorf("Filters = %v, want a single %s filter", r.Filters, tt.wantFilter)
+			}
+		})
+	}
+}
+
+func TestParse_PaginationMissingValue(t *testing.T) {
+	for _, query := range []string{"offset", "limit"} {
+		if _, err := ParseStrict("http://x/users?"+query, Options{Pagination: PaginationOffset}); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", query)
+		}
+	}
+}
+
+func TestParse_PaginationInvalidValue(t *testing.T) {
+	opts := Options{Pagination: PaginationBoth}
+	for _, query := range []string{"limit=abc", "per_page=1.5", "page=two", "offset=x"} {
+		if _, err := ParseStrict("http://x/users?"+query, opts); !errors.Is(err, ErrInvalidValue) {
+			t.Errorf("ParseStrict(%q) error = %v, want %v", query, err, ErrInvalidValue)
+		}
+
+		// Lenient parsing ignores the parameter.
+		r, err := Parse("http://x/users?"+query, opts)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", query, err)
+		}
+		if r.Limit() != defaultPerPage || r.Offset() != 0 || r.Page != 1 {
+			t.Errorf("Parse(%q) Limit/Offset/Page = %d/%d/%d, want the defaults", query, r.Limit(), r.Offset(), r.Page)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package hapi
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParse_ParamNames(t *testing.T) {
+	opts := Options{
+		Pagination: PaginationBoth,
+		Params: ParamNames{
+			Page:    "page[number]",
+			PerPage: "page[size]",
+			Offset:  "skip",
+			Sort:    "$sort",
+		},
+	}
+
+	r, err := ParseStrict("http://x/books?page[number]=3&page[size]=20&$sort=title:asc&page=12&sort=hardcover&limit=5", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Page != 3 || r.Limit() != 5 {
+		t.Errorf("Page/Limit = %d/%d, want 3/5", r.Page, r.Limit())
+	}
+	if want := (Sorts{{Field: "title", Direction: SortDirectionAsc}}); !reflect.DeepEqual(r.Sorts, want) {
+		t.Errorf("Sorts = %v, want %v", r.Sorts, want)
+	}
+	wantFilters := Filters{
+		{Field: "page", Operator: FilterOperatorEqual, Values: Values{"12"}},
+		{Field: "sort", Operator: FilterOperatorEqual, Values: Values{"hardcover"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+
+	r, err = ParseStrict("http://x/books?skip=30&page[size]=10", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Offset() != 30 || r.Page != 4 {
+		t.Errorf("Offset/Page = %d/%d, want 30/4", r.Offset(), r.Page)
+	}
+}
+
+func TestParse_FilterParam(t *testing.T) {
+	opts := Options{FilterParam: "filter"}
+
+	r, err := ParseStrict("http://x/books?filter[page][ge]=100&filter[title]=Dune&filter[or][0][year]=1965&filter[or][1][year]=1984&page=2&utm_source=mail", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilters := Filters{
+		{Field: "page", Operator: FilterOperatorGreaterOrEqual, Values: Values{"100"}},
+		{Field: "title", Operator: FilterOperatorEqual, Values: Values{"Dune"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+	if r.Expr == nil || len(r.Expr.Filters()) != 4 {
+		t.Errorf("Expr = %v, want the two filters and the or group", r.Expr)
+	}
+	if r.Page != 2 {
+		t.Errorf("Page = %d, want 2", r.Page)
+	}
+
+	r, err = ParseStrict("http://x/books?filter[age__gte]=18", Options{FilterParam: "filter", OperatorNotation: OperatorNotationSuffix})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Filters{{Field: "age", Operator: FilterOperatorGreaterOrEqual, Values: Values{"18"}}}); !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %v, want %v", r.Filters, want)
+	}
+
+	for _, query := range []string{"filter[name", "filter[name][xx]=1", "filter[]=bob", "filter[][eq]=bob", "filter[or][0][]=bob"} {
+		if _, err := ParseStrict("http://x/books?"+query, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", query)
+		}
+	}
+	if _, err := ParseStrict("http://x/books?filter[]=bob", opts); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("ParseStrict() error = %v, want %v for an empty field name", err, ErrInvalidFormat)
+	}
+
+	// A namespaced prefix is stripped as a whole.
+	r, err = ParseStrict("http://x/books?q[f][name]=bob&q[f][age][gt]=18&q[g]=x", Options{FilterParam: "q[f]"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFilters = Filters{
+		{Field: "name", Operator: FilterOperatorEqual, Values: Values{"bob"}},
+		{Field: "age", Operator: FilterOperatorGreaterThan, Values: Values{"18"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+}
+
+func TestParse_ParamNamesEncoded(t *testing.T) {
+	opts := Options{Params: ParamNames{Page: "page[number]", PerPage: "page[size]"}}
+	query := url.Values{"page[number]": {"2"}, "page[size]": {"15"}, "age[gt]": {"18"}}.Encode()
+
+	r, err := ParseStrict("http://x/books?"+query, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Page != 2 || r.PerPage != 15 {
+		t.Errorf("Page/PerPage = %d/%d, want 2/15", r.Page, r.PerPage)
+	}
+	if want := (Filters{{Field: "age", Operator: FilterOperatorGreaterThan, Values: Values{"18"}}}); !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %v, want %v", r.Filters, want)
+	}
+}
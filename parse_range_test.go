@@ -0,0 +1,67 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_Range(t *testing.T) {
+	opts := *NewOptions(WithFieldTypes(map[string]FieldType{
+		"age":        FieldTypeInt,
+		"created_at": FieldTypeTime,
+	}))
+
+	r, err := ParseStrict("http://x/users?age[bt]=18,65&created_at[nbtx]=2024-01-01T00:00:00Z,2024-12-31T00:00:00Z&name[bt]=b,a", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Filters{
+		{Field: "age", Operator: FilterOperatorBetween, Values: Values{"18", "65"}},
+		{Field: "created_at", Operator: FilterOperatorNotBetweenExclusive, Values: Values{"2024-01-01T00:00:00Z", "2024-12-31T00:00:00Z"}},
+		// Bounds of untyped fields are not compared.
+		{Field: "name", Operator: FilterOperatorBetween, Values: Values{"b", "a"}},
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %#v, want %#v", r.Filters, want)
+	}
+
+	for _, u := range []string{
+		"http://x/users?age[bt]=18",
+		"http://x/users?age[btx]=1,2,3",
+		"http://x/users?age[bt]=65,18",
+		"http://x/users?created_at[bt]=2024-12-31T00:00:00Z,2024-01-01T00:00:00Z",
+	} {
+		_, err := ParseStrict(u, opts)
+		if err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept invalid filter: %v", u, r.Filters)
+		}
+	}
+}
+
+func TestParse_RangeSyntaxes(t *testing.T) {
+	want := Filters{{Field: "age", Operator: FilterOperatorBetween, Values: Values{"18", "65"}}}
+
+	r, err := ParseStrict("http://x/users?age__range=18,65", *NewOptions(WithOperatorNotation(OperatorNotationSuffix)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("suffix Filters = %#v, want %#v", r.Filters, want)
+	}
+
+	r, err = ParseStrict("http://x/users?q=age=bt=(18,65)", *NewOptions(WithRSQLParam("q")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("RSQL Filters = %#v, want %#v", r.Filters, want)
+	}
+}
@@ -0,0 +1,71 @@
+package hapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_Regexp(t *testing.T) {
+	opts := *NewOptions(WithFieldTypes(map[string]FieldType{"code": FieldTypeInt}))
+
+	r, err := ParseStrict("http://x/logs?message[re]=%5Etimeout%20after%20%5Cd%2Bs&path[nre]=%5C.png%24&code[re]=%5E5", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Filters) != 3 {
+		t.Fatalf("Filters = %v, want 3 filters", r.Filters)
+	}
+
+	tests := []struct {
+		filter   Filter
+		operator FilterOperator
+		pattern  string
+		match    string
+	}{
+		{r.Filters[0], FilterOperatorRegexp, `^timeout after \d+s`, "timeout after 30s"},
+		{r.Filters[1], FilterOperatorNotRegexp, `\.png$`, "logo.png"},
+		// Regular expressions match the textual form of typed fields.
+		{r.Filters[2], FilterOperatorRegexp, `^5`, "503"},
+	}
+	for _, tt := range tests {
+		if tt.filter.Operator != tt.operator || tt.filter.Values.First() != Value(tt.pattern) {
+			t.Errorf("Filter = %v, want %s %q", tt.filter, tt.operator, tt.pattern)
+		}
+		if tt.filter.Regexp == nil || tt.filter.Regexp.String() != tt.pattern || !tt.filter.Regexp.MatchString(tt.match) {
+			t.Errorf("Filter.Regexp = %v, want compiled %q", tt.filter.Regexp, tt.pattern)
+		}
+	}
+}
+
+func TestParse_RegexpErrors(t *testing.T) {
+	opts := *NewOptions(WithMaxPatternLength(16))
+
+	for _, u := range []string{
+		"http://x/logs?message[re]=(unclosed",
+		"http://x/logs?message[nre]=a%2A%2A%2B",
+		"http://x/logs?message[re]=" + strings.Repeat("a", 17),
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) kept invalid filter: %v", u, r.Filters)
+		}
+	}
+}
+
+// Other operators never carry a compiled expression.
+func TestParse_RegexpOnlyForRegexpOperators(t *testing.T) {
+	r, err := ParseStrict("http://x/logs?message[lk]=%5Ea", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Filters[0].Regexp != nil {
+		t.Errorf("Filter.Regexp = %v, want nil", r.Filters[0].Regexp)
+	}
+}
@@ -0,0 +1,102 @@
+package hapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ProblemContentType is the media type of RFC 9457 Problem Details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 9457 Problem Details object describing a rejected query.
+// The invalid parameters are listed in the "errors" extension member.
+type Problem struct {
+	Type   string         `json:"type"`             // URI identifying the problem type ("about:blank" by default)
+	Title  string         `json:"title"`            // Short summary of the problem type
+	Status int            `json:"status"`           // HTTP status code
+	Detail string         `json:"detail,omitempty"` // Explanation of this occurrence
+	Errors []ProblemError `json:"errors,omitempty"` // The invalid query parameters
+}
+
+// ProblemError describes an invalid query parameter in a Problem.
+type ProblemError struct {
+	Code     ErrorCode      `json:"code"`               // Machine-readable error code
+	Param    string         `json:"param,omitempty"`    // The offending parameter
+	Field    string         `json:"field,omitempty"`    // The field concerned
+	Operator FilterOperator `json:"operator,omitempty"` // The filter operator concerned
+	Value    Value          `json:"value,omitempty"`    // The offending value
+	Position int            `json:"position,omitempty"` // Offset of a syntax error in an expression
+	Reason   string         `json:"reason"`             // Human-readable error message
+}
+
+// NewProblem builds the Problem describing err, usually returned by
+// ParseStrict or ParseFromRequestStrict, with the 400 Bad Request status.
+// Each *ParseError found in err is listed in Problem.Errors.
+//
+// The returned Problem may be adjusted, e.g. to set a custom Type, before
+// being written with Problem.Write. A nil err gives a Problem without detail.
+func NewProblem(err error) *Problem {
+	p := &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(http.StatusBadRequest),
+		Status: http.StatusBadRequest,
+	}
+	if err == nil {
+		return p
+	}
+
+	var parseErrs ParseErrors
+	var parseErr *ParseError
+	switch {
+	case errors.As(err, &parseErrs):
+	case errors.As(err, &parseErr):
+		parseErrs = ParseErrors{parseErr}
+	default:
+		p.Detail = err.Error()
+		return p
+	}
+
+	for _, e := range parseErrs {
+		if e == nil {
+			continue
+		}
+		p.Errors = append(p.Errors, ProblemError{
+			Code:     e.Code,
+			Param:    e.Param,
+			Field:    e.Field,
+			Operator: e.Operator,
+			Value:    e.Value,
+			Position: e.Position,
+			Reason:   e.Error(),
+		})
+	}
+
+	if len(p.Errors) == 1 {
+		p.Detail = p.Errors[0].Reason
+	} else if len(p.Errors) > 1 {
+		p.Detail = fmt.Sprintf("%d query parameters are invalid", len(p.Errors))
+	}
+	return p
+}
+
+// Write writes the problem to w as application/problem+json, with the
+// problem status as HTTP status code.
+func (p *Problem) Write(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+	return json.NewEncoder(w).Encode(p)
+}
+
+// WriteProblem writes err to w as an RFC 9457 Problem Details response,
+// see NewProblem.
+//
+//	result, err := hapi.ParseFromRequestStrict(r, opts)
+//	if err != nil {
+//		hapi.WriteProblem(w, err)
+//		return
+//	}
+func WriteProblem(w http.ResponseWriter, err error) error {
+	return NewProblem(err).Write(w)
+}
@@ -0,0 +1,98 @@
+package hapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWriteProblem(t *testing.T) {
+	_, err := ParseStrict("http://x/users?salary=1&age[gt]=abc", Options{
+		AllowedFilters: []string{"age"},
+		FieldTypes:     map[string]FieldType{"age": FieldTypeInt},
+	})
+	if err == nil {
+		t.Fatal("ParseStrict() expected error, got nil")
+	}
+
+	rec := httptest.NewRecorder()
+	if err := WriteProblem(rec, err); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ProblemContentType)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"type":   "about:blank",
+		"title":  "Bad Request",
+		"status": float64(400),
+		"detail": "2 query parameters are invalid",
+		"errors": []any{
+			map[string]any{
+				"code":     "field_not_allowed",
+				"param":    "salary=1",
+				"field":    "salary",
+				"operator": "eq",
+				"reason":   `filtering by field "salary" is not allowed`,
+			},
+			map[string]any{
+				"code":     "invalid_value",
+				"param":    "age[gt]=abc",
+				"field":    "age",
+				"operator": "gt",
+				"value":    "abc",
+				"reason":   `invalid value for field "age": expected int, got "abc"`,
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
+
+func TestNewProblem(t *testing.T) {
+	_, err := ParseStrict("http://x/users?sort=name:up", Options{})
+	p := NewProblem(err)
+	if p.Detail != `invalid sort direction: "up"` || len(p.Errors) != 1 || p.Errors[0].Code != ErrInvalidDirection {
+		t.Errorf("NewProblem() = %+v", p)
+	}
+
+	// Errors that are not parse errors only fill the detail.
+	p = NewProblem(errors.New("request or URL is nil"))
+	if p.Status != http.StatusBadRequest || p.Detail != "request or URL is nil" || p.Errors != nil {
+		t.Errorf("NewProblem() = %+v", p)
+	}
+
+	// A nil error and a parse error without underlying error do not panic.
+	p = NewProblem(nil)
+	if p.Status != http.StatusBadRequest || p.Detail != "" || p.Errors != nil {
+		t.Errorf("NewProblem(nil) = %+v", p)
+	}
+	p = NewProblem(&ParseError{Code: ErrInvalidCursor, Param: "cursor=x"})
+	if p.Detail != "invalid_cursor" || len(p.Errors) != 1 || p.Errors[0].Reason != "invalid_cursor" {
+		t.Errorf("NewProblem() = %+v", p)
+	}
+
+	// The problem can be customized before being written.
+	p.Type = "https://example.com/problems/invalid-query"
+	rec := httptest.NewRecorder()
+	if err := p.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	var body Problem
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Type != p.Type {
+		t.Errorf("body = %+v, %v", body, err)
+	}
+}
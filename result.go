@@ -1,9 +1,52 @@
 package hapi
 
+import "time"
+
 // Result represents the parsed query parameters including filters, sorting, and pagination.
 type Result struct {
-	Filters Filters // Collection of filter conditions
-	Sorts   Sorts   // Sorting configuration
-	Page    int     // Current page number (1-based)
-	PerPage int     // Number of items per page
+	Filters Filters   // Collection of filter conditions, combined with AND
+	Expr    *Expr     // Boolean expression of Filters and filter groups; nil when no group is used
+	Sorts   Sorts     // Sorting configuration
+	Page    int       // Current page number (1-based)
+	PerPage int       // Number of items per page (per_page or limit)
+	Cursor  *Cursor   // Keyset pagination cursor; nil when the query has none
+	Fields  Fields    // Fields to return; nil when every field is returned
+	Now     time.Time // Time of the parse read from Options.Clock, against which relative time values are evaluated; zero without a clock
+
+	Fieldsets map[string][]string // JSON:API sparse fieldsets, resource type -> fields; nil when none is requested
+	Includes  []string            // JSON:API relationship paths to include, e.g. "author.comments"
+
+	offset *int // Explicit number of items to skip (e.g. offset or $skip), overriding the page offset; nil when unset
+}
+
+// Offset returns the number of items to skip before the first item of the page.
+// It is zero for a cursor page, which is selected by Keyset instead.
+func (r Result) Offset() int {
+	if r.Cursor != nil {
+		return 0
+	}
+	if r.offset != nil {
+		return *r.offset
+	}
+	return (max(r.Page, 1) - 1) * r.PerPage
+}
+
+// Limit returns the maximum number of items of the page, i.e. PerPage.
+func (r Result) Limit() int {
+	return r.PerPage
+}
+
+// Where returns the boolean expression every matching item must satisfy.
+// It is Expr when the query uses filter groups, and the conjunction of
+// Filters otherwise.
+func (r Result) Where() Expr {
+	if r.Expr != nil {
+		return *r.Expr
+	}
+
+	exprs := make([]Expr, len(r.Filters))
+	for i, filter := range r.Filters {
+		exprs[i] = FilterExpr(filter)
+	}
+	return And(exprs...)
 }
@@ -0,0 +1,27 @@
+package hapi
+
+import "testing"
+
+func TestResultOffset(t *testing.T) {
+	offset := func(n int) *int { return &n }
+
+	tests := []struct {
+		name   string
+		result Result
+		want   int
+	}{
+		{"First page", Result{Page: 1, PerPage: 10}, 0},
+		{"Third page", Result{Page: 3, PerPage: 25}, 50},
+		{"Zero page", Result{Page: 0, PerPage: 10}, 0},
+		{"Explicit offset", Result{Page: 2, PerPage: 10, offset: offset(15)}, 15},
+		{"Explicit zero offset", Result{Page: 2, PerPage: 10, offset: offset(0)}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.Offset(); got != tt.want {
+				t.Errorf("Offset() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package hapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rsqlOperators maps the RSQL/FIQL comparison operators that are not spelled
+// like a FilterOperator ("=gt=", "=in=", ...) onto FilterOperator.
+var rsqlOperators = map[string]FilterOperator{
+	"==":    FilterOperatorEqual,
+	"!=":    FilterOperatorNotEqual,
+	"<":     FilterOperatorLessThan,
+	"<=":    FilterOperatorLessOrEqual,
+	">":     FilterOperatorGreaterThan,
+	">=":    FilterOperatorGreaterOrEqual,
+	"=out=": FilterOperatorNotIn,
+}
+
+// parseRSQL parses an RSQL/FIQL expression such as
+// "name==John;age=gt=18,status=in=(a,b)" into a boolean expression.
+//
+// ";" (or "and") binds tighter than "," (or "or") and parentheses group
+// constraints. Besides the RSQL operators, every FilterOperator can be written
+// in FIQL form, e.g. "=lk=" or "=inlk=". An unquoted "*" in the argument of
+// "==" or "!=" is a wildcard and turns the comparison into lk or nlk.
+//
+// Every comparison is validated against opts like a bracket filter.
+func parseRSQL(input string, opts Options, maxDepth int) (Expr, error) {
+	p := rsqlParser{input: input, opts: opts, maxDepth: maxDepth}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return Expr{}, err
+	}
+
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return Expr{}, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	return expr, nil
+}
+
+// rsqlParser is a recursive descent parser for RSQL/FIQL expressions.
+type rsqlParser struct {
+	input    string
+	pos      int
+	depth    int
+	maxDepth int
+	opts     Options
+}
+
+// errorf returns an ErrInvalidExpression error at the current position.
+func (p *rsqlParser) errorf(format string, args ...any) *ParseError {
+	return &ParseError{
+		Code:     ErrInvalidExpression,
+		Position: p.pos,
+		Err:      fmt.Errorf("invalid RSQL expression at position %d: %s", p.pos, fmt.Sprintf(format, args...)),
+	}
+}
+
+// parseOr parses constraints separated by "," or "or".
+func (p *rsqlParser) parseOr() (Expr, error) {
+	return p.parseList(LogicalOr, ',', "or", p.parseAnd)
+}
+
+// parseAnd parses constraints separated by ";" or "and".
+func (p *rsqlParser) parseAnd() (Expr, error) {
+	return p.parseList(LogicalAnd, ';', "and", p.parseConstraint)
+}
+
+// parseList parses operands separated by sep or keyword and combines them with op.
+func (p *rsqlParser) parseList(op LogicalOperator, sep byte, keyword string, operand func() (Expr, error)) (Expr, error) {
+	var exprs []Expr
+	for {
+		expr, err := operand()
+		if err != nil {
+			return Expr{}, err
+		}
+		exprs = append(exprs, expr)
+
+		if !p.consumeSeparator(sep, keyword) {
+			break
+		}
+	}
+
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Expr{Operator: op, Children: exprs}, nil
+}
+
+// consumeSeparator consumes sep, or keyword surrounded by spaces.
+func (p *rsqlParser) consumeSeparator(sep byte, keyword string) bool {
+	start := p.pos
+	p.skipSpaces()
+
+	if p.pos < len(p.input) && p.input[p.pos] == sep {
+		p.pos++
+		return true
+	}
+
+	if p.pos > start && strings.HasPrefix(p.input[p.pos:], keyword) {
+		end := p.pos + len(keyword)
+		if end < len(p.input) && (p.input[end] == ' ' || p.input[end] == '(') {
+			p.pos = end
+			return true
+		}
+	}
+
+	p.pos = start
+	return false
+}
+
+// parseConstraint parses a parenthesized group or a comparison.
+func (p *rsqlParser) parseConstraint() (Expr, error) {
+	p.skipSpaces()
+
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+		p.depth++
+		if p.depth > p.maxDepth {
+			err := p.errorf("nesting exceeds the maximum depth of %d", p.maxDepth)
+			err.Code = ErrMaxDepthExceeded
+			return Expr{}, err
+		}
+
+		expr, err := p.parseOr()
+		if err != nil {
+			return Expr{}, err
+		}
+
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return Expr{}, p.errorf("missing closing parenthesis")
+		}
+		p.pos++
+		p.depth--
+		return expr, nil
+	}
+
+	return p.parseComparison()
+}
+
+// parseComparison parses "selector operator arguments".
+func (p *rsqlParser) parseComparison() (Expr, error) {
+	field := p.readWhile(isRSQLUnreserved)
+	if field == "" {
+		return Expr{}, p.errorf("expected field name")
+	}
+
+	p.skipSpaces()
+	operator, err := p.parseOperator()
+	if err != nil {
+		return Expr{}, withField(err, field)
+	}
+
+	p.skipSpaces()
+	values, wildcard, err := p.parseArguments()
+	if err != nil {
+		return Expr{}, err
+	}
+
+	if wildcard && (operator == FilterOperatorEqual || operator == FilterOperatorNotEqual) {
+		if operator == FilterOperatorEqual {
+			operator = FilterOperatorLike
+		} else {
+			operator = FilterOperatorNotLike
+		}
+		// Only "*" is a wildcard: the LIKE wildcards of the value match literally.
+		values[0] = Value(strings.ReplaceAll(EscapeLike(string(values[0])), "*", "%"))
+	}
+
+	if len(values) > 1 && !operator.IsList() {
+		err := p.errorf("operator %q expects a single value", operator)
+		err.Code, err.Field, err.Operator = ErrInvalidValue, field, operator
+		return Expr{}, err
+	}
+
+	filter, err := buildFilter(field, operator, values, p.opts)
+	if err != nil {
+		return Expr{}, err
+	}
+	filter.Escaped = wildcard && operator.IsPattern()
+	return FilterExpr(filter), nil
+}
+
+// parseOperator parses a comparison operator.
+func (p *rsqlParser) parseOperator() (FilterOperator, error) {
+	rest := p.input[p.pos:]
+
+	// FIQL form "=name=".
+	if len(rest) > 1 && rest[0] == '=' && isRSQLLetter(rest[1]) {
+		end := strings.IndexByte(rest[1:], '=')
+		if end < 0 {
+			return "", p.errorf("unterminated operator %q", rest)
+		}
+		token := rest[:end+2]
+		p.pos += len(token)
+
+		if operator, ok := rsqlOperators[token]; ok {
+			return operator, nil
+		}
+		operator := FilterOperator(token[1 : len(token)-1])
+		if err := operator.Valid(); err != nil {
+			return "", err
+		}
+		return operator, nil
+	}
+
+	for _, token := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(rest, token) {
+			p.pos += len(token)
+			return rsqlOperators[token], nil
+		}
+	}
+
+	return "", p.errorf("expected comparison operator")
+}
+
+// parseArguments parses a single value or a parenthesized list of values.
+// It reports whether a single unquoted value contains a "*" wildcard.
+func (p *rsqlParser) parseArguments() (Values, bool, error) {
+	if p.pos < len(p.input) && p.input[p.pos] == '(' {
+		p.pos++
+
+		var values Values
+		for {
+			p.skipSpaces()
+			value, _, err := p.parseValue()
+			if err != nil {
+				return nil, false, err
+			}
+			values = append(values, value)
+
+			p.skipSpaces()
+			if p.pos < len(p.input) && p.input[p.pos] == ',' {
+				p.pos++
+				continue
+			}
+			if p.pos < len(p.input) && p.input[p.pos] == ')' {
+				p.pos++
+				return values, false, nil
+			}
+			return nil, false, p.errorf("expected \",\" or \")\" in argument list")
+		}
+	}
+
+	value, quoted, err := p.parseValue()
+	if err != nil {
+		return nil, false, err
+	}
+	return Values{value}, !quoted && strings.Contains(string(value), "*"), nil
+}
+
+// parseValue parses a quoted or unquoted value and reports whether it was quoted.
+func (p *rsqlParser) parseValue() (Value, bool, error) {
+	if p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\'') {
+		quote := p.input[p.pos]
+		p.pos++
+
+		var sb strings.Builder
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			p.pos++
+			switch {
+			case c == '\\' && p.pos < len(p.input):
+				sb.WriteByte(p.input[p.pos])
+				p.pos++
+			case c == quote:
+				return Value(sb.String()), true, nil
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", false, p.errorf("unterminated quoted value")
+	}
+
+	value := p.readWhile(isRSQLValueChar)
+	if value == "" {
+		return "", false, p.errorf("expected value")
+	}
+	return Value(value), false, nil
+}
+
+// readWhile consumes and returns the longest run of bytes accepted by accept.
+func (p *rsqlParser) readWhile(accept func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.input) && accept(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *rsqlParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// isRSQLValueChar reports whether c may appear in an unquoted value.
+func isRSQLValueChar(c byte) bool {
+	return !strings.ContainsRune(" \"'();,", rune(c))
+}
+
+// isRSQLUnreserved reports whether c may appear in a field name.
+func isRSQLUnreserved(c byte) bool {
+	return isRSQLValueChar(c) && !strings.ContainsRune("=!~<>", rune(c))
+}
+
+func isRSQLLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
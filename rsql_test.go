@@ -0,0 +1,166 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRSQL(t *testing.T) {
+	leaf := func(field string, op FilterOperator, values ...Value) Expr {
+		return FilterExpr(Filter{Field: field, Operator: op, Values: values})
+	}
+	escaped := func(field string, op FilterOperator, values ...Value) Expr {
+		return FilterExpr(Filter{Field: field, Operator: op, Values: values, Escaped: true})
+	}
+
+	tests := []struct {
+		input string
+		want  Expr
+	}{
+		{"name==John", leaf("name", FilterOperatorEqual, "John")},
+		{"name!=John", leaf("name", FilterOperatorNotEqual, "John")},
+		{"age=gt=18", leaf("age", FilterOperatorGreaterThan, "18")},
+		{"age>=18", leaf("age", FilterOperatorGreaterOrEqual, "18")},
+		{"age<18", leaf("age", FilterOperatorLessThan, "18")},
+		{"age=le=65", leaf("age", FilterOperatorLessOrEqual, "65")},
+		{"status=in=(a,b)", leaf("status", FilterOperatorIn, "a", "b")},
+		{"status=out=( a , b )", leaf("status", FilterOperatorNotIn, "a", "b")},
+		{"status=in=a", leaf("status", FilterOperatorIn, "a")},
+		{"tags=inlk=(go%,%api)", leaf("tags", FilterOperatorInLike, "go%", "%api")},
+		{"name==Jo*", escaped("name", FilterOperatorLike, "Jo%")},
+		{"name!=*spam*", escaped("name", FilterOperatorNotLike, "%spam%")},
+		{`name=="Jo*"`, leaf("name", FilterOperatorEqual, "Jo*")},
+		{"name==50%_*", escaped("name", FilterOperatorLike, `50\%\_%`)},
+		{`name=='John Doe'`, leaf("name", FilterOperatorEqual, "John Doe")},
+		{`name=="say \"hi\""`, leaf("name", FilterOperatorEqual, `say "hi"`)},
+		{
+			"name==John;age=gt=18,status=in=(a,b)",
+			Or(
+				And(leaf("name", FilterOperatorEqual, "John"), leaf("age", FilterOperatorGreaterThan, "18")),
+				leaf("status", FilterOperatorIn, "a", "b"),
+			),
+		},
+		{
+			"name==John;(age=gt=18,status==a)",
+			And(
+				leaf("name", FilterOperatorEqual, "John"),
+				Or(leaf("age", FilterOperatorGreaterThan, "18"), leaf("status", FilterOperatorEqual, "a")),
+			),
+		},
+		{
+			"name==John and (age=gt=18 or status==a)",
+			And(
+				leaf("name", FilterOperatorEqual, "John"),
+				Or(leaf("age", FilterOperatorGreaterThan, "18"), leaf("status", FilterOperatorEqual, "a")),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRSQL(tt.input, Options{}, defaultMaxDepth)
+			if err != nil {
+				t.Fatalf("parseRSQL() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRSQL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRSQLErrors(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"name",
+		"name==",
+		"==John",
+		"name=xx=John",
+		"name=gt",
+		"name==John;",
+		"(name==John",
+		"name==John)",
+		`name=="John`,
+		"name==(a,b)",
+		"status=in=(a,b",
+		"((((a==1))))",
+	} {
+		if _, err := parseRSQL(input, Options{}, defaultMaxDepth); err == nil {
+			t.Errorf("parseRSQL(%q) expected error, got nil", input)
+		}
+	}
+}
+
+func TestParse_RSQL(t *testing.T) {
+	opts := Options{RSQLParam: "filter"}
+
+	r, err := ParseStrict("http://x/users?filter=name==John;age=gt=18&sort=name:asc", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Filters{
+		{Field: "name", Operator: FilterOperatorEqual, Values: Values{"John"}},
+		{Field: "age", Operator: FilterOperatorGreaterThan, Values: Values{"18"}},
+	}
+	if !reflect.DeepEqual(r.Filters, want) || r.Expr != nil {
+		t.Errorf("Filters = %v, Expr = %v, want flat filters %v", r.Filters, r.Expr, want)
+	}
+
+	r, err = ParseStrict("http://x/users?role=admin&filter=status==active,owner==me", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	role := Filter{Field: "role", Operator: FilterOperatorEqual, Values: Values{"admin"}}
+	wantExpr := And(
+		FilterExpr(role),
+		Or(
+			FilterExpr(Filter{Field: "status", Operator: FilterOperatorEqual, Values: Values{"active"}}),
+			FilterExpr(Filter{Field: "owner", Operator: FilterOperatorEqual, Values: Values{"me"}}),
+		),
+	)
+	if !reflect.DeepEqual(r.Filters, Filters{role}) {
+		t.Errorf("Filters = %v, want %v", r.Filters, Filters{role})
+	}
+	if r.Expr == nil || !reflect.DeepEqual(*r.Expr, wantExpr) {
+		t.Errorf("Expr = %v, want %v", r.Expr, wantExpr)
+	}
+
+	// URL-encoded expressions and "+" for spaces.
+	r, err = ParseStrict("http://x/users?filter=name%3D%3D%27John+Doe%27", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Filters.GetFirstFromField("name").Values.First(); got != "John Doe" {
+		t.Errorf("name = %q, want %q", got, "John Doe")
+	}
+}
+
+// RSQL comparisons go through the same validation as bracket filters.
+func TestParse_RSQLValidation(t *testing.T) {
+	opts := Options{
+		RSQLParam:        "filter",
+		AllowedFilters:   []string{"name", "age"},
+		AllowedOperators: map[string][]FilterOperator{"age": {FilterOperatorGreaterThan}},
+		FieldTypes:       map[string]FieldType{"age": FieldTypeInt},
+	}
+
+	for _, u := range []string{
+		"http://x/users?filter=salary==1",
+		"http://x/users?filter=name==a,age==1",
+		"http://x/users?filter=age=gt=abc",
+		"http://x/users?filter=name==",
+		"http://x/users?filter",
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", u, err)
+		}
+		if len(r.Filters) != 0 || r.Expr != nil {
+			t.Errorf("Parse(%q) kept part of an invalid expression: %v", u, r.Where())
+		}
+	}
+}
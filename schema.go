@@ -0,0 +1,181 @@
+package hapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// schemaCache holds the Options reflected from each struct type by SchemaFor.
+var schemaCache sync.Map // map[reflect.Type]schemaEntry
+
+type schemaEntry struct {
+	opts Options
+	err  error
+}
+
+// SchemaFor builds Options from the `hapi` struct tags of T, so a model struct
+// can be the single source of truth for what a query may filter and sort on.
+// Reflection runs once per type; later calls reuse the cached schema.
+//
+// The tag format is `hapi:"name,option,..."` with the following options:
+//
+//	filter           the field may be filtered with any operator
+//	filter=eq|in     the field may be filtered with the listed operators only
+//	sort             the field may be sorted on
+//	type=int         the value type of the field (inferred from the Go type when omitted)
+//	enum=a|b         the field is an enum accepting the listed values
+//	column=u.name    the storage column the field maps to
+//
+// An empty name defaults to the Go field name and a tag of "-" skips the field.
+// When any field declares a column, every other schema field is kept in the
+// field map without a column, so fields outside the schema can be neither
+// filtered nor sorted on while schema fields are still quoted by translators.
+//
+// Note that a schema without any filterable (or sortable) field leaves
+// AllowedFilters (or AllowedSorts) empty, which allows every field.
+//
+// The given option functions are applied on top of the reflected schema.
+func SchemaFor[T any](opts ...OptionFunc) (*Options, error) {
+	t := reflect.TypeFor[T]()
+
+	entry, ok := schemaCache.Load(t)
+	if !ok {
+		schema, err := reflectSchema(t)
+		entry, _ = schemaCache.LoadOrStore(t, schemaEntry{opts: schema, err: err})
+	}
+
+	schema := entry.(schemaEntry)
+	if schema.err != nil {
+		return nil, schema.err
+	}
+
+	options := schema.opts.clone()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &options, nil
+}
+
+// MustSchemaFor is like SchemaFor but panics if the struct tags of T are invalid.
+// It simplifies initialization of package-level option variables.
+func MustSchemaFor[T any](opts ...OptionFunc) *Options {
+	options, err := SchemaFor[T](opts...)
+	if err != nil {
+		panic(err)
+	}
+	return options
+}
+
+// reflectSchema builds Options from the struct tags of t.
+func reflectSchema(t reflect.Type) (Options, error) {
+	if t.Kind() != reflect.Struct {
+		return Options{}, fmt.Errorf("hapi: schema type %s is not a struct", t)
+	}
+
+	options := *NewOptions()
+	options.AllowedOperators = make(map[string][]FilterOperator)
+	options.FieldMap = make(map[string]string)
+	options.FieldTypes = make(map[string]FieldType)
+	options.EnumValues = make(map[string][]string)
+
+	// VisibleFields also yields the fields promoted from embedded structs.
+	hasColumn := false
+	for _, sf := range reflect.VisibleFields(t) {
+		tag, tagged := sf.Tag.Lookup("hapi")
+		if !tagged || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		name, tagOptions := parseTag(tag)
+		if name == "" {
+			name = sf.Name
+		}
+
+		column := ""
+		fieldType, hasType := inferFieldType(sf.Type)
+		for _, option := range tagOptions {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "filter":
+				options.AllowedFilters = append(options.AllowedFilters, name)
+				if value == "" {
+					continue
+				}
+				for _, op := range strings.Split(value, "|") {
+					operator := FilterOperator(op)
+					if err := operator.Valid(); err != nil {
+						return Options{}, fmt.Errorf("hapi: field %s of %s: %w", sf.Name, t, err)
+					}
+					options.AllowedOperators[name] = append(options.AllowedOperators[name], operator)
+				}
+			case "sort":
+				options.AllowedSorts = append(options.AllowedSorts, name)
+			case "type":
+				fieldType, hasType = FieldType(value), true
+				if err := fieldType.Valid(); err != nil {
+					return Options{}, fmt.Errorf("hapi: field %s of %s: %w", sf.Name, t, err)
+				}
+			case "enum":
+				fieldType, hasType = FieldTypeEnum, true
+				options.EnumValues[name] = strings.Split(value, "|")
+			case "column":
+				column = value
+				hasColumn = true
+			default:
+				return Options{}, fmt.Errorf("hapi: field %s of %s: unknown tag option %q", sf.Name, t, option)
+			}
+		}
+
+		if hasType {
+			options.FieldTypes[name] = fieldType
+		}
+		options.FieldMap[name] = column
+	}
+
+	if !hasColumn {
+		options.FieldMap = nil
+	}
+
+	return options, nil
+}
+
+// parseTag splits a `hapi` struct tag into the field name and its options.
+func parseTag(tag string) (string, []string) {
+	name, rest, _ := strings.Cut(tag, ",")
+	if rest == "" {
+		return name, nil
+	}
+	return name, strings.Split(rest, ",")
+}
+
+// inferFieldType returns the FieldType matching a Go type, if any.
+func inferFieldType(t reflect.Type) (FieldType, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeFor[time.Time]() {
+		return FieldTypeTime, true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return FieldTypeString, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return FieldTypeInt, true
+	case reflect.Int64:
+		return FieldTypeInt64, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return FieldTypeUint, true
+	case reflect.Float32, reflect.Float64:
+		return FieldTypeFloat, true
+	case reflect.Bool:
+		return FieldTypeBool, true
+	}
+
+	return "", false
+}
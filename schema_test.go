@@ -0,0 +1,200 @@
+package hapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type schemaUser struct {
+	ID        string    `hapi:"id,filter=eq|in,type=uuid"`
+	Name      string    `hapi:"name,filter=eq|lk,sort,column=u.name"`
+	Age       int       `hapi:"age,filter=gt|ge|lt|le,sort"`
+	Status    string    `hapi:"status,filter,enum=active|pending"`
+	CreatedAt time.Time `hapi:"created,sort,column=u.created_at"`
+	Password  string    `hapi:"-"`
+	Internal  string
+}
+
+func TestSchemaFor(t *testing.T) {
+	opts, err := SchemaFor[schemaUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opts.DefaultPerPage != defaultPerPage || opts.MaxPerPage != defaultMaxPerPage {
+		t.Errorf("pagination = %d/%d, want defaults", opts.DefaultPerPage, opts.MaxPerPage)
+	}
+	if want := []string{"id", "name", "age", "status"}; !reflect.DeepEqual(opts.AllowedFilters, want) {
+		t.Errorf("AllowedFilters = %v, want %v", opts.AllowedFilters, want)
+	}
+	if want := []string{"name", "age", "created"}; !reflect.DeepEqual(opts.AllowedSorts, want) {
+		t.Errorf("AllowedSorts = %v, want %v", opts.AllowedSorts, want)
+	}
+
+	wantOperators := map[string][]FilterOperator{
+		"id":   {FilterOperatorEqual, FilterOperatorIn},
+		"name": {FilterOperatorEqual, FilterOperatorLike},
+		"age":  {FilterOperatorGreaterThan, FilterOperatorGreaterOrEqual, FilterOperatorLessThan, FilterOperatorLessOrEqual},
+	}
+	if !reflect.DeepEqual(opts.AllowedOperators, wantOperators) {
+		t.Errorf("AllowedOperators = %v, want %v", opts.AllowedOperators, wantOperators)
+	}
+
+	wantTypes := map[string]FieldType{
+		"id":      FieldTypeUUID,
+		"name":    FieldTypeString,
+		"age":     FieldTypeInt,
+		"status":  FieldTypeEnum,
+		"created": FieldTypeTime,
+	}
+	if !reflect.DeepEqual(opts.FieldTypes, wantTypes) {
+		t.Errorf("FieldTypes = %v, want %v", opts.FieldTypes, wantTypes)
+	}
+	if want := map[string][]string{"status": {"active", "pending"}}; !reflect.DeepEqual(opts.EnumValues, want) {
+		t.Errorf("EnumValues = %v, want %v", opts.EnumValues, want)
+	}
+
+	wantMap := map[string]string{
+		"id":      "",
+		"name":    "u.name",
+		"age":     "",
+		"status":  "",
+		"created": "u.created_at",
+	}
+	if !reflect.DeepEqual(opts.FieldMap, wantMap) {
+		t.Errorf("FieldMap = %v, want %v", opts.FieldMap, wantMap)
+	}
+}
+
+func TestSchemaForUnsigned(t *testing.T) {
+	type item struct {
+		Stock uint   `hapi:"stock,filter"`
+		Views uint64 `hapi:"views,filter"`
+		Order int    `hapi:"order,filter,sort"`
+		Owner string `hapi:"owner,filter,column=o.name"`
+	}
+
+	opts, err := SchemaFor[item]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.FieldTypes["stock"] != FieldTypeUint || opts.FieldTypes["views"] != FieldTypeUint {
+		t.Errorf("FieldTypes = %v, want uint for unsigned fields", opts.FieldTypes)
+	}
+	if _, err := ParseStrict("http://x/items?stock[gt]=-1", *opts); err == nil {
+		t.Error("ParseStrict() expected error for a negative uint, got nil")
+	}
+
+	r, err := ParseStrict("http://x/items?order=1&sort=order:asc", *opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Filters[0].Column != "" || r.Sorts[0].Column != "" {
+		t.Errorf("order column = %q/%q, want unmapped so translators quote it", r.Filters[0].Column, r.Sorts[0].Column)
+	}
+	if _, err := ParseStrict("http://x/items?secret=1", *opts); err == nil {
+		t.Error("ParseStrict() expected error for a field outside the schema, got nil")
+	}
+}
+
+func TestSchemaForWithoutColumns(t *testing.T) {
+	type item struct {
+		Name string `hapi:",filter,sort"`
+	}
+
+	opts, err := SchemaFor[item](WithMaxPerPage(50))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.FieldMap != nil {
+		t.Errorf("FieldMap = %v, want nil when no column is declared", opts.FieldMap)
+	}
+	if !reflect.DeepEqual(opts.AllowedFilters, []string{"Name"}) {
+		t.Errorf("AllowedFilters = %v, want [Name]", opts.AllowedFilters)
+	}
+	if opts.MaxPerPage != 50 {
+		t.Errorf("MaxPerPage = %d, want 50", opts.MaxPerPage)
+	}
+}
+
+func TestSchemaForIsCachedAndIsolated(t *testing.T) {
+	first, err := SchemaFor[schemaUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first.AllowedFilters[0] = "tampered"
+	first.FieldMap["name"] = "tampered"
+	first.AllowedOperators["id"][0] = FilterOperatorLike
+
+	second, err := SchemaFor[schemaUser]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.AllowedFilters[0] != "id" || second.FieldMap["name"] != "u.name" || second.AllowedOperators["id"][0] != FilterOperatorEqual {
+		t.Error("SchemaFor returned options sharing state with a previous call")
+	}
+
+	if _, ok := schemaCache.Load(reflect.TypeFor[schemaUser]()); !ok {
+		t.Error("schema was not cached")
+	}
+}
+
+func TestSchemaForInvalidTags(t *testing.T) {
+	type badOperator struct {
+		Name string `hapi:"name,filter=eq|xx"`
+	}
+	type badType struct {
+		Name string `hapi:"name,type=decimal"`
+	}
+	type badOption struct {
+		Name string `hapi:"name,search"`
+	}
+
+	if _, err := SchemaFor[badOperator](); err == nil {
+		t.Error("SchemaFor[badOperator] expected error, got nil")
+	}
+	if _, err := SchemaFor[badType](); err == nil {
+		t.Error("SchemaFor[badType] expected error, got nil")
+	}
+	if _, err := SchemaFor[badOption](); err == nil {
+		t.Error("SchemaFor[badOption] expected error, got nil")
+	}
+	if _, err := SchemaFor[int](); err == nil {
+		t.Error("SchemaFor[int] expected error, got nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustSchemaFor expected panic, got none")
+		}
+	}()
+	MustSchemaFor[badOption]()
+}
+
+func TestSchemaForParse(t *testing.T) {
+	opts := MustSchemaFor[schemaUser]()
+
+	r, err := ParseStrict("http://x/users?name[lk]=Jo%25&age[ge]=18&sort=created:desc", *opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Filters.GetFirstFromField("name").Column; got != "u.name" {
+		t.Errorf("name column = %q, want u.name", got)
+	}
+	if got := r.Sorts[0].Column; got != "u.created_at" {
+		t.Errorf("sort column = %q, want u.created_at", got)
+	}
+
+	for _, u := range []string{
+		"http://x/users?Password=x",
+		"http://x/users?age[lk]=1%25",
+		"http://x/users?age[gt]=old",
+		"http://x/users?status=banned",
+		"http://x/users?sort=status:asc",
+	} {
+		if _, err := ParseStrict(u, *opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+	}
+}
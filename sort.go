@@ -10,25 +10,34 @@ type Sorts []Sort
 
 // Sort represents a sorting configuration with field and direction.
 type Sort struct {
-	Field     string        `json:"field"`     // The field to sort by
+	Field     string        `json:"field"`     // The field to sort by, as sent by the client
+	Column    string        `json:"-"`         // The storage column mapped from Field (empty without Options.FieldMap)
 	Direction SortDirection `json:"direction"` // The sort direction (asc or desc)
 }
 
+// ColumnName returns the storage column of the sort, falling back to Field when unmapped.
+func (s Sort) ColumnName() string {
+	if s.Column != "" {
+		return s.Column
+	}
+	return s.Field
+}
+
 // parseSortFromString parses a sort string in the format "field:direction".
 func parseSortFromString(value string) (Sort, error) {
 	if value == "" {
-		return Sort{}, fmt.Errorf("sort value cannot be empty")
+		return Sort{}, &ParseError{Code: ErrInvalidSort, Err: fmt.Errorf("sort value cannot be empty")}
 	}
 
 	part := strings.Split(value, ":")
 	if len(part) != 2 {
-		return Sort{}, fmt.Errorf("invalid sort format: expected 'field:direction', got %q", value)
+		return Sort{}, &ParseError{Code: ErrInvalidSort, Value: Value(value), Err: fmt.Errorf("invalid sort format: expected 'field:direction', got %q", value)}
 	}
 
 	// Allow empty field for backward compatibility (though not recommended)
 	direction := SortDirection(part[1])
 	if err := direction.Valid(); err != nil {
-		return Sort{}, err
+		return Sort{}, withField(err, part[0])
 	}
 
 	return Sort{
@@ -0,0 +1,293 @@
+// Package sqlbuilder turns a parsed hapi.Result into parameterized SQL clauses.
+// Values are never interpolated into the query: they are returned as bind
+// arguments, and field names are quoted according to the selected Dialect.
+package sqlbuilder
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ermos/hapi"
+)
+
+// arrayOperators maps the hapi array operators onto PostgreSQL array operators.
+var arrayOperators = map[hapi.FilterOperator]string{
+	hapi.FilterOperatorContainsAll: "@>",
+	hapi.FilterOperatorOverlaps:    "&&",
+	hapi.FilterOperatorContainedBy: "<@",
+}
+
+// Clause holds the SQL fragments built from a hapi.Result.
+type Clause struct {
+	Select  string // Column list of the projection, without the SELECT keyword (empty = every column)
+	Where   string // Filter conditions, without the WHERE keyword
+	OrderBy string // Sort list, without the ORDER BY keyword
+	Limit   string // Dialect-specific pagination, e.g. "LIMIT 10 OFFSET 20"
+	Args    []any  // Bind arguments referenced by the placeholders in Where, in order
+}
+
+// String assembles the clause into a query tail that can be appended to a
+// SELECT statement, e.g. " WHERE ... ORDER BY ... LIMIT 10 OFFSET 0".
+// Select is not part of the tail, as it goes between SELECT and FROM.
+func (c Clause) String() string {
+	var sb strings.Builder
+	if c.Where != "" {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(c.Where)
+	}
+	if c.OrderBy != "" {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(c.OrderBy)
+	}
+	if c.Limit != "" {
+		sb.WriteString(" ")
+		sb.WriteString(c.Limit)
+	}
+	return sb.String()
+}
+
+// Build converts the projection, filter expression, sorts and pagination of a
+// hapi.Result into a parameterized Clause for the given dialect.
+// Returns an error if d is not one of the predefined dialects or a filter uses
+// an operator that cannot be translated.
+//
+// A cursor page is selected by the keyset predicate of the result instead of
+// an offset. A page before a cursor is ordered in reverse, so its rows must be
+// reversed before display.
+func Build(r hapi.Result, d Dialect) (Clause, error) {
+	if d.placeholder == nil {
+		return Clause{}, fmt.Errorf("sqlbuilder: invalid dialect: use one of the predefined dialects")
+	}
+	b := builder{dialect: d}
+
+	// The operands of a top-level conjunction are rendered without the
+	// surrounding parentheses; any other root is rendered as a whole.
+	roots := []hapi.Expr{r.Where()}
+	if roots[0].Operator == hapi.LogicalAnd && !roots[0].IsLeaf() {
+		roots = roots[0].Children
+	}
+	if keyset, ok := r.Keyset(); ok {
+		roots = append(roots[:len(roots):len(roots)], keyset)
+	}
+	conditions := make([]string, 0, len(roots))
+	for _, expr := range roots {
+		condition, err := b.expr(expr)
+		if err != nil {
+			return Clause{}, err
+		}
+		conditions = append(conditions, condition)
+	}
+
+	reverse := r.Cursor != nil && r.Cursor.Before
+	orders := make([]string, 0, len(r.Sorts))
+	for _, sort := range r.Sorts {
+		direction := "ASC"
+		if (sort.Direction == hapi.SortDirectionDesc) != reverse {
+			direction = "DESC"
+		}
+		orders = append(orders, b.column(sort.Field, sort.Column)+" "+direction)
+	}
+
+	columns := make([]string, len(r.Fields))
+	for i, field := range r.Fields {
+		columns[i] = b.column(field.Name, field.Column)
+		if field.Column != "" {
+			// Mapped columns are aliased back to the API field name.
+			columns[i] += " AS " + d.QuoteIdent(field.Name)
+		}
+	}
+
+	clause := Clause{
+		Select:  strings.Join(columns, ", "),
+		Where:   strings.Join(conditions, " AND "),
+		OrderBy: strings.Join(orders, ", "),
+		Args:    b.args,
+	}
+
+	if r.Limit() > 0 {
+		clause.Limit = d.limit(r.Limit(), r.Offset())
+		if r.Cursor != nil {
+			clause.Limit = d.fetch(r.Limit())
+		}
+		// SQL Server only accepts OFFSET/FETCH after an ORDER BY.
+		if clause.OrderBy == "" && d.name == SQLServer.name {
+			clause.OrderBy = "(SELECT NULL)"
+		}
+	}
+
+	return clause, nil
+}
+
+// builder accumulates bind arguments while rendering conditions.
+type builder struct {
+	dialect Dialect
+	args    []any
+}
+
+// bind registers a bind argument and returns its placeholder.
+func (b *builder) bind(v hapi.Value) string {
+	b.args = append(b.args, v.String())
+	return b.dialect.Placeholder(len(b.args))
+}
+
+// like renders a LIKE comparison of column against the pattern of v under
+// operator. escaped tells whether the pattern was escaped with hapi.EscapeLike,
+// in which case it is evaluated with "\" as escape character; patterns sent
+// as-is by the client keep the default escaping of the database.
+func (b *builder) like(column string, operator hapi.FilterOperator, v hapi.Value, negate, fold, escaped bool) string {
+	pattern := operator.LikePattern(v)
+	if escaped {
+		pattern = b.dialect.escapeLike(pattern)
+	}
+	return b.dialect.like(column, b.bind(hapi.Value(pattern)), negate, fold, escaped)
+}
+
+// column returns the SQL expression for a field. Columns mapped through
+// hapi.Options.FieldMap come from server configuration and are used verbatim,
+// while raw client field names are always quoted.
+func (b *builder) column(field, column string) string {
+	if column != "" {
+		return column
+	}
+	return b.dialect.QuoteIdent(field)
+}
+
+// expr renders a boolean expression as a SQL condition.
+func (b *builder) expr(e hapi.Expr) (string, error) {
+	if e.IsLeaf() {
+		return b.filter(*e.Filter)
+	}
+
+	conditions := make([]string, len(e.Children))
+	for i, child := range e.Children {
+		condition, err := b.expr(child)
+		if err != nil {
+			return "", err
+		}
+		conditions[i] = condition
+	}
+
+	switch e.Operator {
+	case hapi.LogicalNot:
+		if len(conditions) != 1 {
+			return "", fmt.Errorf("sqlbuilder: not expression expects 1 operand, got %d", len(conditions))
+		}
+		return "NOT (" + conditions[0] + ")", nil
+	case hapi.LogicalAnd, hapi.LogicalOr:
+		if len(conditions) == 0 {
+			// An empty conjunction matches everything, an empty disjunction nothing.
+			if e.Operator == hapi.LogicalAnd {
+				return "1 = 1", nil
+			}
+			return "1 = 0", nil
+		}
+		if len(conditions) == 1 {
+			return conditions[0], nil
+		}
+		return "(" + strings.Join(conditions, " "+strings.ToUpper(string(e.Operator))+" ") + ")", nil
+	}
+
+	return "", fmt.Errorf("sqlbuilder: unsupported logical operator %q", e.Operator)
+}
+
+// filter renders a single filter as a SQL condition.
+func (b *builder) filter(f hapi.Filter) (string, error) {
+	column := b.column(f.Field, f.Column)
+
+	switch f.Operator {
+	case hapi.FilterOperatorEqual:
+		return column + " = " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorNotEqual:
+		return column + " <> " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorGreaterThan:
+		return column + " > " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorLessThan:
+		return column + " < " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorGreaterOrEqual:
+		return column + " >= " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorLessOrEqual:
+		return column + " <= " + b.bind(f.Values.First()), nil
+	case hapi.FilterOperatorStartsWith, hapi.FilterOperatorEndsWith, hapi.FilterOperatorContains:
+		return b.like(column, f.Operator, f.Values.First(), false, false, true), nil
+	case hapi.FilterOperatorLike:
+		return b.like(column, f.Operator, f.Values.First(), false, false, f.Escaped), nil
+	case hapi.FilterOperatorNotLike:
+		return b.like(column, f.Operator, f.Values.First(), true, false, f.Escaped), nil
+	case hapi.FilterOperatorILike:
+		return b.like(column, f.Operator, f.Values.First(), false, true, f.Escaped), nil
+	case hapi.FilterOperatorNotILike:
+		return b.like(column, f.Operator, f.Values.First(), true, true, f.Escaped), nil
+	case hapi.FilterOperatorIn, hapi.FilterOperatorNotIn:
+		if len(f.Values) == 0 {
+			// An empty set matches nothing, its negation matches everything.
+			if f.Operator == hapi.FilterOperatorIn {
+				return "1 = 0", nil
+			}
+			return "1 = 1", nil
+		}
+		placeholders := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			placeholders[i] = b.bind(v)
+		}
+		keyword := " IN ("
+		if f.Operator == hapi.FilterOperatorNotIn {
+			keyword = " NOT IN ("
+		}
+		return column + keyword + strings.Join(placeholders, ", ") + ")", nil
+	case hapi.FilterOperatorInLike, hapi.FilterOperatorNotInLike:
+		// inlk matches any of the patterns, ninlk matches none of them.
+		negate, separator, empty := false, " OR ", "1 = 0"
+		if f.Operator == hapi.FilterOperatorNotInLike {
+			negate, separator, empty = true, " AND ", "1 = 1"
+		}
+		if len(f.Values) == 0 {
+			return empty, nil
+		}
+		conditions := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			conditions[i] = b.like(column, f.Operator, v, negate, false, f.Escaped)
+		}
+		if len(conditions) == 1 {
+			return conditions[0], nil
+		}
+		return "(" + strings.Join(conditions, separator) + ")", nil
+	case hapi.FilterOperatorRegexp, hapi.FilterOperatorNotRegexp:
+		condition, ok := b.dialect.regexp(column, b.bind(f.Values.First()), f.Operator == hapi.FilterOperatorNotRegexp)
+		if !ok {
+			return "", fmt.Errorf("sqlbuilder: operator %q on field %q is not supported by %s", f.Operator, f.Field, b.dialect)
+		}
+		return condition, nil
+	case hapi.FilterOperatorContainsAll, hapi.FilterOperatorOverlaps, hapi.FilterOperatorContainedBy:
+		placeholders := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			placeholders[i] = b.bind(v)
+		}
+		condition, ok := b.dialect.array(column, arrayOperators[f.Operator], placeholders)
+		if !ok {
+			return "", fmt.Errorf("sqlbuilder: operator %q on field %q is not supported by %s", f.Operator, f.Field, b.dialect)
+		}
+		return condition, nil
+	case hapi.FilterOperatorIsNull:
+		return column + " IS NULL", nil
+	case hapi.FilterOperatorNotNull:
+		return column + " IS NOT NULL", nil
+	case hapi.FilterOperatorBetween, hapi.FilterOperatorNotBetween,
+		hapi.FilterOperatorBetweenExclusive, hapi.FilterOperatorNotBetweenExclusive:
+		if len(f.Values) != 2 {
+			return "", fmt.Errorf("sqlbuilder: operator %q on field %q expects 2 values, got %d", f.Operator, f.Field, len(f.Values))
+		}
+		lower, upper := b.bind(f.Values[0]), b.bind(f.Values[1])
+		switch f.Operator {
+		case hapi.FilterOperatorBetween:
+			return column + " BETWEEN " + lower + " AND " + upper, nil
+		case hapi.FilterOperatorNotBetween:
+			return column + " NOT BETWEEN " + lower + " AND " + upper, nil
+		case hapi.FilterOperatorBetweenExclusive:
+			return "(" + column + " > " + lower + " AND " + column + " < " + upper + ")", nil
+		default:
+			return "(" + column + " <= " + lower + " OR " + column + " >= " + upper + ")", nil
+		}
+	}
+
+	return "", fmt.Errorf("sqlbuilder: unsupported operator %q on field %q", f.Operator, f.Field)
+}
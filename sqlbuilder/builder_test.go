@@ -0,0 +1,384 @@
+package sqlbuilder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ermos/hapi"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  hapi.Result
+		dialect Dialect
+		want    Clause
+	}{
+		{
+			name:    "Empty result",
+			result:  hapi.Result{},
+			dialect: Postgres,
+			want:    Clause{},
+		},
+		{
+			name: "Projection",
+			result: hapi.Result{Fields: hapi.Fields{
+				{Name: "id"},
+				{Name: "name"},
+				{Name: "created", Column: "u.created_at"},
+			}},
+			dialect: MySQL,
+			want: Clause{
+				Select: "`id`, `name`, u.created_at AS `created`",
+			},
+		},
+		{
+			name: "Comparison operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "name", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"John"}},
+				{Field: "status", Operator: hapi.FilterOperatorNotEqual, Values: hapi.Values{"banned"}},
+				{Field: "age", Operator: hapi.FilterOperatorGreaterOrEqual, Values: hapi.Values{"18"}},
+				{Field: "age", Operator: hapi.FilterOperatorLessThan, Values: hapi.Values{"65"}},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"name" = $1 AND "status" <> $2 AND "age" >= $3 AND "age" < $4`,
+				Args:  []any{"John", "banned", "18", "65"},
+			},
+		},
+		{
+			name: "List operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "status", Operator: hapi.FilterOperatorIn, Values: hapi.Values{"active", "pending"}},
+				{Field: "role", Operator: hapi.FilterOperatorNotIn, Values: hapi.Values{"admin"}},
+			}},
+			dialect: MySQL,
+			want: Clause{
+				Where: "`status` IN (?, ?) AND `role` NOT IN (?)",
+				Args:  []any{"active", "pending", "admin"},
+			},
+		},
+		{
+			name: "Like list operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "tags", Operator: hapi.FilterOperatorInLike, Values: hapi.Values{"go%", "%api"}},
+				{Field: "name", Operator: hapi.FilterOperatorNotInLike, Values: hapi.Values{"a%", "b%"}},
+				{Field: "email", Operator: hapi.FilterOperatorNotLike, Values: hapi.Values{"%spam%"}},
+			}},
+			dialect: SQLServer,
+			want: Clause{
+				Where: `([tags] LIKE @p1 OR [tags] LIKE @p2) AND ([name] NOT LIKE @p3 AND [name] NOT LIKE @p4) AND [email] NOT LIKE @p5`,
+				Args:  []any{"go%", "%api", "a%", "b%", "%spam%"},
+			},
+		},
+		{
+			name: "String-matching operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "name", Operator: hapi.FilterOperatorStartsWith, Values: hapi.Values{"Jo"}},
+				{Field: "email", Operator: hapi.FilterOperatorEndsWith, Values: hapi.Values{"@example.com"}},
+				{Field: "title", Operator: hapi.FilterOperatorContains, Values: hapi.Values{`50%_off\`}},
+				{Field: "city", Operator: hapi.FilterOperatorILike, Values: hapi.Values{"par%"}},
+				{Field: "country", Operator: hapi.FilterOperatorNotILike, Values: hapi.Values{"fr%"}},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"name" LIKE $1 AND "email" LIKE $2 AND "title" LIKE $3 AND "city" ILIKE $4 AND "country" NOT ILIKE $5`,
+				Args:  []any{"Jo%", "%@example.com", `%50\%\_off\\%`, "par%", "fr%"},
+			},
+		},
+		{
+			name: "Case-insensitive like without ILIKE",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "city", Operator: hapi.FilterOperatorILike, Values: hapi.Values{"par%"}},
+				{Field: "country", Operator: hapi.FilterOperatorNotILike, Values: hapi.Values{"fr%"}},
+			}},
+			dialect: SQLite,
+			want: Clause{
+				Where: `LOWER("city") LIKE LOWER(?) AND LOWER("country") NOT LIKE LOWER(?)`,
+				Args:  []any{"par%", "fr%"},
+			},
+		},
+		{
+			name: "Escaped patterns on SQL Server",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "title", Operator: hapi.FilterOperatorContains, Values: hapi.Values{"[a]_"}},
+				{Field: "name", Operator: hapi.FilterOperatorLike, Values: hapi.Values{`[J]o\_%`}, Escaped: true},
+				{Field: "code", Operator: hapi.FilterOperatorLike, Values: hapi.Values{"[A-C]%"}},
+			}},
+			dialect: SQLServer,
+			want: Clause{
+				Where: `[title] LIKE @p1 ESCAPE '\' AND [name] LIKE @p2 ESCAPE '\' AND [code] LIKE @p3`,
+				Args:  []any{`%\[a]\_%`, `\[J]o\_%`, "[A-C]%"},
+			},
+		},
+		{
+			name: "Regular expressions",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "message", Operator: hapi.FilterOperatorRegexp, Values: hapi.Values{"^timeout"}},
+				{Field: "path", Operator: hapi.FilterOperatorNotRegexp, Values: hapi.Values{`\.png$`}},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"message" ~ $1 AND "path" !~ $2`,
+				Args:  []any{"^timeout", `\.png$`},
+			},
+		},
+		{
+			name: "Regular expressions on MySQL",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "message", Operator: hapi.FilterOperatorRegexp, Values: hapi.Values{"^timeout"}},
+				{Field: "path", Operator: hapi.FilterOperatorNotRegexp, Values: hapi.Values{`\.png$`}},
+			}},
+			dialect: MySQL,
+			want: Clause{
+				Where: "`message` REGEXP ? AND `path` NOT REGEXP ?",
+				Args:  []any{"^timeout", `\.png$`},
+			},
+		},
+		{
+			name: "Array operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "tags", Operator: hapi.FilterOperatorContainsAll, Values: hapi.Values{"go", "api"}},
+				{Field: "tags", Operator: hapi.FilterOperatorOverlaps, Values: hapi.Values{"rust"}},
+				{Field: "roles", Operator: hapi.FilterOperatorContainedBy, Values: hapi.Values{"admin", "user"}},
+				{Field: "labels", Operator: hapi.FilterOperatorContainedBy},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"tags" @> ARRAY[$1, $2] AND "tags" && ARRAY[$3] AND "roles" <@ ARRAY[$4, $5] AND "labels" <@ '{}'`,
+				Args:  []any{"go", "api", "rust", "admin", "user"},
+			},
+		},
+		{
+			name: "Range operators",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "age", Operator: hapi.FilterOperatorBetween, Values: hapi.Values{"18", "65"}},
+				{Field: "score", Operator: hapi.FilterOperatorNotBetween, Values: hapi.Values{"1", "2"}},
+				{Field: "price", Operator: hapi.FilterOperatorBetweenExclusive, Values: hapi.Values{"10", "20"}},
+				{Field: "rank", Operator: hapi.FilterOperatorNotBetweenExclusive, Values: hapi.Values{"3", "7"}},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"age" BETWEEN $1 AND $2 AND "score" NOT BETWEEN $3 AND $4 AND ("price" > $5 AND "price" < $6) AND ("rank" <= $7 OR "rank" >= $8)`,
+				Args:  []any{"18", "65", "1", "2", "10", "20", "3", "7"},
+			},
+		},
+		{
+			name: "Null checks",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "deleted_at", Operator: hapi.FilterOperatorIsNull},
+				{Field: "email", Operator: hapi.FilterOperatorNotNull},
+				{Field: "name", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{""}},
+			}},
+			dialect: MySQL,
+			want: Clause{
+				Where: "`deleted_at` IS NULL AND `email` IS NOT NULL AND `name` = ?",
+				Args:  []any{""},
+			},
+		},
+		{
+			name: "Empty lists",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: "status", Operator: hapi.FilterOperatorIn},
+				{Field: "status", Operator: hapi.FilterOperatorNotInLike},
+			}},
+			dialect: Postgres,
+			want:    Clause{Where: "1 = 0 AND 1 = 1"},
+		},
+		{
+			name: "Filter groups",
+			result: hapi.Result{Expr: func() *hapi.Expr {
+				e := hapi.And(
+					hapi.FilterExpr(hapi.Filter{Field: "age", Operator: hapi.FilterOperatorGreaterOrEqual, Values: hapi.Values{"18"}}),
+					hapi.Or(
+						hapi.FilterExpr(hapi.Filter{Field: "status", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"active"}}),
+						hapi.FilterExpr(hapi.Filter{Field: "owner", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"me"}}),
+					),
+					hapi.Not(hapi.FilterExpr(hapi.Filter{Field: "role", Operator: hapi.FilterOperatorIn, Values: hapi.Values{"admin", "root"}})),
+				)
+				return &e
+			}()},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"age" >= $1 AND ("status" = $2 OR "owner" = $3) AND NOT ("role" IN ($4, $5))`,
+				Args:  []any{"18", "active", "me", "admin", "root"},
+			},
+		},
+		{
+			name:    "Leaf root",
+			result:  hapi.Result{Expr: exprPtr(hapi.FilterExpr(hapi.Filter{Field: "owner", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"me"}}))},
+			dialect: Postgres,
+			want:    Clause{Where: `"owner" = $1`, Args: []any{"me"}},
+		},
+		{
+			name: "Or root",
+			result: hapi.Result{Expr: exprPtr(hapi.Or(
+				hapi.FilterExpr(hapi.Filter{Field: "owner", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"me"}}),
+				hapi.FilterExpr(hapi.Filter{Field: "status", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"public"}}),
+			))},
+			dialect: Postgres,
+			want:    Clause{Where: `("owner" = $1 OR "status" = $2)`, Args: []any{"me", "public"}},
+		},
+		{
+			name:    "Not root",
+			result:  hapi.Result{Expr: exprPtr(hapi.Not(hapi.FilterExpr(hapi.Filter{Field: "owner", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"me"}})))},
+			dialect: Postgres,
+			want:    Clause{Where: `NOT ("owner" = $1)`, Args: []any{"me"}},
+		},
+		{
+			name: "Sorts and pagination",
+			result: hapi.Result{
+				Sorts: hapi.Sorts{
+					{Field: "created_at", Direction: hapi.SortDirectionDesc},
+					{Field: "name", Direction: hapi.SortDirectionAsc},
+				},
+				Page:    3,
+				PerPage: 20,
+			},
+			dialect: SQLite,
+			want: Clause{
+				OrderBy: `"created_at" DESC, "name" ASC`,
+				Limit:   "LIMIT 20 OFFSET 40",
+			},
+		},
+		{
+			name:    "SQL Server pagination without sort",
+			result:  hapi.Result{Page: 2, PerPage: 10},
+			dialect: SQLServer,
+			want: Clause{
+				OrderBy: "(SELECT NULL)",
+				Limit:   "OFFSET 10 ROWS FETCH NEXT 10 ROWS ONLY",
+			},
+		},
+		{
+			name: "Mapped columns are used verbatim",
+			result: hapi.Result{
+				Filters: hapi.Filters{
+					{Field: "created", Column: "u.created_at", Operator: hapi.FilterOperatorGreaterThan, Values: hapi.Values{"2024-01-01"}},
+				},
+				Sorts: hapi.Sorts{{Field: "name", Column: "lower(u.name)", Direction: hapi.SortDirectionAsc}},
+			},
+			dialect: Postgres,
+			want: Clause{
+				Where:   "u.created_at > $1",
+				OrderBy: "lower(u.name) ASC",
+				Args:    []any{"2024-01-01"},
+			},
+		},
+		{
+			name: "Injection attempt stays in arguments and quotes",
+			result: hapi.Result{Filters: hapi.Filters{
+				{Field: `name" OR 1=1 --`, Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"' OR '1'='1"}},
+			}},
+			dialect: Postgres,
+			want: Clause{
+				Where: `"name"" OR 1=1 --" = $1`,
+				Args:  []any{"' OR '1'='1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(tt.result, tt.dialect)
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func exprPtr(e hapi.Expr) *hapi.Expr {
+	return &e
+}
+
+func TestBuildUnsupportedOperator(t *testing.T) {
+	r := hapi.Result{Filters: hapi.Filters{{Field: "name", Operator: "xx", Values: hapi.Values{"a"}}}}
+	if _, err := Build(r, Postgres); err == nil {
+		t.Error("Build() expected error for unsupported operator, got nil")
+	}
+}
+
+func TestBuildUnsupportedDialectOperator(t *testing.T) {
+	r := hapi.Result{Filters: hapi.Filters{{Field: "message", Operator: hapi.FilterOperatorRegexp, Values: hapi.Values{"^a"}}}}
+	if _, err := Build(r, SQLServer); err == nil {
+		t.Error("Build() expected error for regular expression on SQL Server, got nil")
+	}
+
+	r = hapi.Result{Filters: hapi.Filters{{Field: "tags", Operator: hapi.FilterOperatorOverlaps, Values: hapi.Values{"go"}}}}
+	if _, err := Build(r, MySQL); err == nil {
+		t.Error("Build() expected error for array operator on MySQL, got nil")
+	}
+}
+
+func TestBuildInvalidDialect(t *testing.T) {
+	r := hapi.Result{Filters: hapi.Filters{{Field: "name", Operator: hapi.FilterOperatorEqual, Values: hapi.Values{"a"}}}}
+	if _, err := Build(r, Dialect{}); err == nil {
+		t.Error("Build() expected error for the zero dialect, got nil")
+	}
+}
+
+func TestClauseString(t *testing.T) {
+	r, err := hapi.Parse("http://x/users?name=John&age[ge]=18&sort=name:asc&page=2&per_page=5", hapi.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Build(r, Postgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ` WHERE "name" = $1 AND "age" >= $2 ORDER BY "name" ASC LIMIT 5 OFFSET 5`
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(c.Args, []any{"John", "18"}) {
+		t.Errorf("Args = %v, want [John 18]", c.Args)
+	}
+}
+
+func TestBuildCursor(t *testing.T) {
+	opts := hapi.Options{CursorKey: []byte("secret")}
+	first, err := hapi.Parse("http://x/users?sort=name:asc,id:desc&per_page=5", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := opts.NextCursor(first, "John", 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		param   string
+		dialect Dialect
+		want    string
+	}{
+		{"after", Postgres, ` WHERE "status" = $1 AND ("name" > $2 OR ("name" = $3 AND "id" < $4)) ORDER BY "name" ASC, "id" DESC LIMIT 5`},
+		{"before", Postgres, ` WHERE "status" = $1 AND ("name" < $2 OR ("name" = $3 AND "id" > $4)) ORDER BY "name" DESC, "id" ASC LIMIT 5`},
+		{"after", SQLServer, ` WHERE [status] = @p1 AND ([name] > @p2 OR ([name] = @p3 AND [id] < @p4)) ORDER BY [name] ASC, [id] DESC OFFSET 0 ROWS FETCH NEXT 5 ROWS ONLY`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.param+"/"+tt.dialect.String(), func(t *testing.T) {
+			r, err := hapi.ParseStrict("http://x/users?status=active&sort=name:asc,id:desc&per_page=5&page=3&"+tt.param+"="+token, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c, err := Build(r, tt.dialect)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := c.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(c.Args, []any{"active", "John", "John", "42"}) {
+				t.Errorf("Args = %v, want [active John John 42]", c.Args)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package sqlbuilder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect describes how a specific SQL database spells placeholders,
+// identifiers and pagination. Only the predefined dialects are valid: the
+// zero Dialect is rejected by Build, and its Placeholder and QuoteIdent
+// follow standard SQL, with "?" placeholders and double-quoted identifiers.
+type Dialect struct {
+	name        string
+	placeholder func(n int) string
+	quoteOpen   string
+	quoteClose  string
+}
+
+// Supported SQL dialects.
+var (
+	// Postgres uses "$1" placeholders and double-quoted identifiers.
+	Postgres = Dialect{
+		name:        "postgres",
+		placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		quoteOpen:   `"`,
+		quoteClose:  `"`,
+	}
+	// MySQL uses "?" placeholders and backtick-quoted identifiers.
+	MySQL = Dialect{
+		name:        "mysql",
+		placeholder: func(int) string { return "?" },
+		quoteOpen:   "`",
+		quoteClose:  "`",
+	}
+	// SQLite uses "?" placeholders and double-quoted identifiers.
+	SQLite = Dialect{
+		name:        "sqlite",
+		placeholder: func(int) string { return "?" },
+		quoteOpen:   `"`,
+		quoteClose:  `"`,
+	}
+	// SQLServer uses "@p1" placeholders and bracket-quoted identifiers.
+	SQLServer = Dialect{
+		name:        "sqlserver",
+		placeholder: func(n int) string { return "@p" + strconv.Itoa(n) },
+		quoteOpen:   "[",
+		quoteClose:  "]",
+	}
+)
+
+// String returns the name of the dialect.
+func (d Dialect) String() string {
+	return d.name
+}
+
+// Placeholder returns the bind placeholder for the n-th (1-based) argument.
+func (d Dialect) Placeholder(n int) string {
+	if d.placeholder == nil {
+		return "?"
+	}
+	return d.placeholder(n)
+}
+
+// QuoteIdent quotes an identifier for use in a query.
+// Dotted names such as "users.name" are quoted part by part.
+func (d Dialect) QuoteIdent(ident string) string {
+	openQuote, closeQuote := d.quoteOpen, d.quoteClose
+	if openQuote == "" {
+		openQuote, closeQuote = `"`, `"`
+	}
+
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		parts[i] = openQuote + strings.ReplaceAll(part, closeQuote, closeQuote+closeQuote) + closeQuote
+	}
+	return strings.Join(parts, ".")
+}
+
+// limit renders the pagination tail of a query.
+func (d Dialect) limit(limit, offset int) string {
+	if d.name == SQLServer.name {
+		return fmt.Sprintf("OFFSET %d ROWS FETCH NEXT %d ROWS ONLY", offset, limit)
+	}
+	return fmt.Sprintf("LIMIT %d OFFSET %d", limit, offset)
+}
+
+// fetch renders the pagination tail of a keyset query, which skips no rows.
+func (d Dialect) fetch(limit int) string {
+	if d.name == SQLServer.name {
+		return d.limit(limit, 0)
+	}
+	return fmt.Sprintf("LIMIT %d", limit)
+}
+
+// like renders a LIKE comparison of column against the pattern placeholder.
+// fold makes the comparison case-insensitive, and escaped evaluates the
+// pattern with "\" as escape character, as produced by hapi.EscapeLike.
+func (d Dialect) like(column, pattern string, negate, fold, escaped bool) string {
+	keyword := "LIKE"
+	if fold {
+		if d.name == Postgres.name {
+			keyword = "ILIKE"
+		} else {
+			column, pattern = "LOWER("+column+")", "LOWER("+pattern+")"
+		}
+	}
+	if negate {
+		keyword = "NOT " + keyword
+	}
+
+	condition := column + " " + keyword + " " + pattern
+	if escaped && (d.name == SQLite.name || d.name == SQLServer.name) {
+		// Unlike PostgreSQL and MySQL, these have no default escape character.
+		condition += ` ESCAPE '\'`
+	}
+	return condition
+}
+
+// escapeLike completes a pattern escaped with hapi.EscapeLike with the
+// escapes of the wildcards specific to the dialect: SQL Server also treats
+// "[" as a wildcard, opening a character class.
+func (d Dialect) escapeLike(pattern string) string {
+	if d.name == SQLServer.name {
+		return strings.ReplaceAll(pattern, "[", `\[`)
+	}
+	return pattern
+}
+
+// regexp renders a regular expression match of column against the pattern
+// placeholder. It reports false when the dialect has no regular expressions.
+func (d Dialect) regexp(column, pattern string, negate bool) (string, bool) {
+	switch d.name {
+	case Postgres.name:
+		if negate {
+			return column + " !~ " + pattern, true
+		}
+		return column + " ~ " + pattern, true
+	case MySQL.name, SQLite.name:
+		// SQLite only provides REGEXP when a regexp() function is registered.
+		if negate {
+			return column + " NOT REGEXP " + pattern, true
+		}
+		return column + " REGEXP " + pattern, true
+	}
+	return "", false
+}
+
+// array renders the comparison of an array column with an array of the
+// placeholders. It reports false when the dialect has no array operators.
+func (d Dialect) array(column, operator string, placeholders []string) (string, bool) {
+	if d.name != Postgres.name {
+		return "", false
+	}
+	if len(placeholders) == 0 {
+		// An untyped empty literal takes the array type of the column.
+		return column + " " + operator + " '{}'", true
+	}
+	return column + " " + operator + " ARRAY[" + strings.Join(placeholders, ", ") + "]", true
+}
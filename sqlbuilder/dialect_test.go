@@ -0,0 +1,52 @@
+package sqlbuilder
+
+import "testing"
+
+func TestDialectPlaceholder(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		n       int
+		want    string
+	}{
+		{Postgres, 1, "$1"},
+		{Postgres, 12, "$12"},
+		{MySQL, 3, "?"},
+		{SQLite, 3, "?"},
+		{SQLServer, 2, "@p2"},
+		{Dialect{}, 2, "?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.String(), func(t *testing.T) {
+			if got := tt.dialect.Placeholder(tt.n); got != tt.want {
+				t.Errorf("Placeholder(%d) = %q, want %q", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDialectQuoteIdent(t *testing.T) {
+	tests := []struct {
+		dialect Dialect
+		ident   string
+		want    string
+	}{
+		{Postgres, "name", `"name"`},
+		{Postgres, "users.name", `"users"."name"`},
+		{Postgres, `na"me`, `"na""me"`},
+		{MySQL, "name", "`name`"},
+		{MySQL, "na`me", "`na``me`"},
+		{SQLite, "users.name", `"users"."name"`},
+		{SQLServer, "name", "[name]"},
+		{SQLServer, "na]me", "[na]]me]"},
+		{Dialect{}, `users.na"me`, `"users"."na""me"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect.String()+"/"+tt.ident, func(t *testing.T) {
+			if got := tt.dialect.QuoteIdent(tt.ident); got != tt.want {
+				t.Errorf("QuoteIdent(%q) = %s, want %s", tt.ident, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package sqlbuilder_test
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/ermos/hapi"
+	"github.com/ermos/hapi/sqlbuilder"
+)
+
+func ExampleBuild() {
+	url := "http://api.example.com/users?name[lk]=John%25&status[in]=active,pending&sort=created_at:desc&page=2&per_page=25"
+
+	result, err := hapi.Parse(url, hapi.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	clause, err := sqlbuilder.Build(result, sqlbuilder.Postgres)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println("SELECT * FROM users" + clause.String())
+	fmt.Println(clause.Args)
+
+	// Output:
+	// SELECT * FROM users WHERE "name" LIKE $1 AND "status" IN ($2, $3) ORDER BY "created_at" DESC LIMIT 25 OFFSET 25
+	// [John% active pending]
+}
@@ -0,0 +1,77 @@
+package hapi
+
+import (
+	"slices"
+	"strings"
+)
+
+// suffixOperators maps Django-style lookup suffixes that are not spelled like
+// a FilterOperator onto FilterOperator.
+var suffixOperators = map[string]FilterOperator{
+	"exact":        FilterOperatorEqual,
+	"gte":          FilterOperatorGreaterOrEqual,
+	"lte":          FilterOperatorLessOrEqual,
+	"range":        FilterOperatorBetween,
+	"contains":     FilterOperatorContains,
+	"startswith":   FilterOperatorStartsWith,
+	"endswith":     FilterOperatorEndsWith,
+	"regex":        FilterOperatorRegexp,
+	"overlap":      FilterOperatorOverlaps,
+	"contained_by": FilterOperatorContainedBy,
+}
+
+// foldSuffixes maps the case-insensitive Django lookups onto the operator
+// whose LIKE pattern they match with ilk.
+var foldSuffixes = map[string]FilterOperator{
+	"iexact":      FilterOperatorEqual,
+	"icontains":   FilterOperatorContains,
+	"istartswith": FilterOperatorStartsWith,
+	"iendswith":   FilterOperatorEndsWith,
+}
+
+// splitSuffix splits a key written in suffix notation, such as "age__gte",
+// into its field and operator. The suffix is either a Django lookup or the
+// name of a FilterOperator, e.g. "age__ge"; an unknown suffix is returned as
+// the operator, which is then rejected as invalid. Keys without a suffix, or
+// declared whole in the options, are returned with the eq operator, so field
+// names may contain "__".
+//
+// fold reports a case-insensitive lookup such as "name__icontains": the
+// returned operator is the case-sensitive one, see foldLookup.
+func splitSuffix(key string, opts Options) (field string, operator FilterOperator, fold bool) {
+	i := strings.LastIndex(key, "__")
+	if i <= 0 || opts.declares(key) {
+		return key, FilterOperatorEqual, false
+	}
+
+	field, suffix := key[:i], key[i+2:]
+	if operator, ok := suffixOperators[suffix]; ok {
+		return field, operator, false
+	}
+	if operator, ok := foldSuffixes[suffix]; ok {
+		return field, operator, true
+	}
+	return field, FilterOperator(suffix), false
+}
+
+// foldLookup turns the values of a case-insensitive lookup under operator
+// into the escaped LIKE patterns matched by the ilk operator.
+func foldLookup(operator FilterOperator, values Values) (FilterOperator, Values) {
+	patterns := make(Values, len(values))
+	for i, v := range values {
+		if operator == FilterOperatorEqual {
+			patterns[i] = Value(EscapeLike(string(v)))
+		} else {
+			patterns[i] = Value(operator.LikePattern(v))
+		}
+	}
+	return FilterOperatorILike, patterns
+}
+
+// declares reports whether field is declared by the allowed filters, the
+// field map or the field types.
+func (o Options) declares(field string) bool {
+	_, mapped := o.FieldMap[field]
+	_, typed := o.FieldTypes[field]
+	return mapped || typed || slices.Contains(o.AllowedFilters, field)
+}
@@ -0,0 +1,183 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitSuffix(t *testing.T) {
+	tests := []struct {
+		key      string
+		field    string
+		operator FilterOperator
+		fold     bool
+	}{
+		{"age", "age", FilterOperatorEqual, false},
+		{"name__exact", "name", FilterOperatorEqual, false},
+		{"age__gt", "age", FilterOperatorGreaterThan, false},
+		{"age__gte", "age", FilterOperatorGreaterOrEqual, false},
+		{"age__lt", "age", FilterOperatorLessThan, false},
+		{"age__lte", "age", FilterOperatorLessOrEqual, false},
+		{"age__range", "age", FilterOperatorBetween, false},
+		{"deleted_at__isnull", "deleted_at", FilterOperatorIsNull, false},
+		{"name__startswith", "name", FilterOperatorStartsWith, false},
+		{"name__endswith", "name", FilterOperatorEndsWith, false},
+		{"title__contains", "title", FilterOperatorContains, false},
+		{"message__regex", "message", FilterOperatorRegexp, false},
+		{"tags__overlap", "tags", FilterOperatorOverlaps, false},
+		{"tags__contained_by", "tags", FilterOperatorContainedBy, false},
+		{"tags__all", "tags", FilterOperatorContainsAll, false},
+		{"status__in", "status", FilterOperatorIn, false},
+		{"status__nin", "status", FilterOperatorNotIn, false},
+		{"name__lk", "name", FilterOperatorLike, false},
+		{"name__iexact", "name", FilterOperatorEqual, true},
+		{"name__icontains", "name", FilterOperatorContains, true},
+		{"name__istartswith", "name", FilterOperatorStartsWith, true},
+		{"name__iendswith", "name", FilterOperatorEndsWith, true},
+		{"user__name__gte", "user__name", FilterOperatorGreaterOrEqual, false},
+		{"user__name", "user", "name", false},
+		{"owner__name", "owner__name", FilterOperatorEqual, false},
+		{"__gt", "__gt", FilterOperatorEqual, false},
+		{"age__", "age", "", false},
+	}
+
+	opts := Options{AllowedFilters: []string{"owner__name"}}
+	for _, tt := range tests {
+		field, operator, fold := splitSuffix(tt.key, opts)
+		if field != tt.field || operator != tt.operator || fold != tt.fold {
+			t.Errorf("splitSuffix(%q) = %q, %q, %v, want %q, %q, %v", tt.key, field, operator, fold, tt.field, tt.operator, tt.fold)
+		}
+	}
+}
+
+func TestParse_SuffixNotationFold(t *testing.T) {
+	opts := Options{OperatorNotation: OperatorNotationSuffix}
+
+	r, err := ParseStrict("http://x/users?name__icontains=jo&email__iexact=A_B%40x.io&city__istartswith=par&country__iendswith=ce", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Filters{
+		{Field: "name", Operator: FilterOperatorILike, Values: Values{"%jo%"}, Escaped: true},
+		{Field: "email", Operator: FilterOperatorILike, Values: Values{`A\_B@x.io`}, Escaped: true},
+		{Field: "city", Operator: FilterOperatorILike, Values: Values{"par%"}, Escaped: true},
+		{Field: "country", Operator: FilterOperatorILike, Values: Values{"%ce"}, Escaped: true},
+	}
+	if !reflect.DeepEqual(r.Filters, want) {
+		t.Errorf("Filters = %v, want %v", r.Filters, want)
+	}
+}
+
+func TestParse_SuffixNotationUnknown(t *testing.T) {
+	opts := Options{OperatorNotation: OperatorNotationSuffix}
+
+	for _, u := range []string{
+		"http://x/users?name__foo=jo",
+		"http://x/users?age__=18",
+	} {
+		if _, err := ParseStrict(u, opts); !errors.Is(err, ErrInvalidOperator) {
+			t.Errorf("ParseStrict(%q) error = %v, want %v", u, err, ErrInvalidOperator)
+		}
+
+		r, err := Parse(u, opts)
+		if err != nil {
+			t.Errorf("Parse(%q) unexpected error: %v", u, err)
+		}
+		if len(r.Filters) != 0 {
+			t.Errorf("Parse(%q) Filters = %v, want none", u, r.Filters)
+		}
+	}
+
+	// A field declared with "__" in its name is not split.
+	opts.FieldMap = map[string]string{"user__name": "users.name"}
+	r, err := ParseStrict("http://x/users?user__name=jo", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := r.Filters.GetFirstFromField("user__name"); f.Operator != FilterOperatorEqual || f.Column != "users.name" {
+		t.Errorf("Filters = %v, want user__name eq", r.Filters)
+	}
+}
+
+func TestParse_SuffixNotation(t *testing.T) {
+	opts := Options{OperatorNotation: OperatorNotationSuffix}
+
+	r, err := ParseStrict("http://x/users?age__gte=18&status__in=a,b&name=John&or[0][role__exact]=admin&or[0][lead]=true", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFilters := Filters{
+		{Field: "age", Operator: FilterOperatorGreaterOrEqual, Values: Values{"18"}},
+		{Field: "status", Operator: FilterOperatorIn, Values: Values{"a", "b"}},
+		{Field: "name", Operator: FilterOperatorEqual, Values: Values{"John"}},
+	}
+	if !reflect.DeepEqual(r.Filters, wantFilters) {
+		t.Errorf("Filters = %v, want %v", r.Filters, wantFilters)
+	}
+	if r.Expr == nil || len(r.Expr.Filters()) != 5 || r.Expr.Filters()[3].Field != "role" {
+		t.Errorf("Expr = %v, want the role__exact group filter mapped to role", r.Expr)
+	}
+
+	// Brackets are not operators in suffix-only notation.
+	if _, err := ParseStrict("http://x/users?age[gt]=18", opts); err == nil {
+		t.Error("ParseStrict() expected error for bracket operator in suffix notation, got nil")
+	}
+}
+
+func TestParse_OperatorNotations(t *testing.T) {
+	tests := []struct {
+		notation OperatorNotation
+		url      string
+		want     Filters
+	}{
+		{
+			notation: OperatorNotationBracket,
+			url:      "http://x/users?age__gte=18",
+			want:     Filters{{Field: "age__gte", Operator: FilterOperatorEqual, Values: Values{"18"}}},
+		},
+		{
+			notation: OperatorNotationBoth,
+			url:      "http://x/users?age__gte=18&age[lt]=65",
+			want: Filters{
+				{Field: "age", Operator: FilterOperatorGreaterOrEqual, Values: Values{"18"}},
+				{Field: "age", Operator: FilterOperatorLessThan, Values: Values{"65"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.notation), func(t *testing.T) {
+			r, err := ParseStrict(tt.url, Options{OperatorNotation: tt.notation})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(r.Filters, tt.want) {
+				t.Errorf("Filters = %v, want %v", r.Filters, tt.want)
+			}
+		})
+	}
+}
+
+// Suffix filters go through the same allowlists as bracket filters.
+func TestParse_SuffixNotationValidation(t *testing.T) {
+	opts := Options{
+		OperatorNotation: OperatorNotationSuffix,
+		AllowedFilters:   []string{"age"},
+		AllowedOperators: map[string][]FilterOperator{"age": {FilterOperatorGreaterOrEqual}},
+	}
+
+	if _, err := ParseStrict("http://x/users?age__gte=18", opts); err != nil {
+		t.Errorf("ParseStrict() unexpected error: %v", err)
+	}
+	for _, u := range []string{
+		"http://x/users?age__lt=18",
+		"http://x/users?salary__gte=1",
+	} {
+		if _, err := ParseStrict(u, opts); err == nil {
+			t.Errorf("ParseStrict(%q) expected error, got nil", u)
+		}
+	}
+}
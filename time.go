@@ -0,0 +1,143 @@
+package hapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unixMillisThreshold is the magnitude from which an integer time value is
+// read as Unix milliseconds rather than seconds: 1e11 seconds is in the year
+// 5138, while 1e11 milliseconds is in 1973.
+const unixMillisThreshold = 1e11
+
+// Time converts the value to a time, returning the zero time if conversion fails.
+// See ParseTime for the accepted formats.
+func (v Value) Time() time.Time {
+	return v.TimeAt(time.Now())
+}
+
+// TimeAt converts the value to a time like Time, evaluating relative
+// expressions against now.
+func (v Value) TimeAt(now time.Time) time.Time {
+	t, err := v.ParseTimeAt(now)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParseTime converts the value to a time, evaluating relative expressions
+// against the current time. Use ParseTimeAt with Result.Now to evaluate them
+// against the clock of the parse, when set. See ParseTimeAt for the accepted
+// formats.
+func (v Value) ParseTime() (time.Time, error) {
+	return v.ParseTimeAt(time.Now())
+}
+
+// ParseTimeAt converts the value to a time, evaluating relative expressions
+// against now. It accepts:
+//
+//	2024-01-31T12:00:00Z    an RFC 3339 timestamp
+//	2024-01-31              a date, at midnight in the location of now
+//	1706702400              Unix seconds
+//	1706702400000           Unix milliseconds (from 12 digits)
+//	now, today              now, or today at midnight; also yesterday and tomorrow
+//	now-7d, today+1d-2h     a relative time shifted by s, m, h, d or w units
+//
+// Since "+" decodes to a space in query strings, a space is read as "+" in
+// relative expressions, so "now+1h" works whether or not "+" was encoded.
+func (v Value) ParseTimeAt(now time.Time) (time.Time, error) {
+	s := string(v)
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, now.Location()); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n >= unixMillisThreshold || n <= -unixMillisThreshold {
+			return time.UnixMilli(n).UTC(), nil
+		}
+		return time.Unix(n, 0).UTC(), nil
+	}
+	if t, ok := parseRelativeTime(s, now); ok {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time value %q", v)
+}
+
+// parseRelativeTime parses an anchor (now, today, yesterday or tomorrow)
+// followed by any number of signed offsets such as "-7d" or "+2h". Each
+// offset starts with "+", "-" or a space read as "+".
+func parseRelativeTime(s string, now time.Time) (time.Time, bool) {
+	anchor, offsets := s, ""
+	if i := strings.IndexAny(s, "+- "); i >= 0 {
+		anchor, offsets = s[:i], s[i:]
+	}
+
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, now.Location())
+
+	var t time.Time
+	switch anchor {
+	case "now":
+		t = now
+	case "today":
+		t = today
+	case "yesterday":
+		t = today.AddDate(0, 0, -1)
+	case "tomorrow":
+		t = today.AddDate(0, 0, 1)
+	default:
+		return time.Time{}, false
+	}
+
+	for offsets != "" {
+		var sign int
+		switch offsets[0] {
+		case '+', ' ':
+			sign = 1
+		case '-':
+			sign = -1
+		default:
+			return time.Time{}, false
+		}
+		offsets = offsets[1:]
+
+		digits := 0
+		for digits < len(offsets) && '0' <= offsets[digits] && offsets[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 || digits == len(offsets) {
+			return time.Time{}, false
+		}
+		n, err := strconv.Atoi(offsets[:digits])
+		if err != nil {
+			return time.Time{}, false
+		}
+		n *= sign
+
+		// Days and weeks are calendar units, so they keep the time of day across DST changes.
+		switch offsets[digits] {
+		case 's':
+			t = t.Add(time.Duration(n) * time.Second)
+		case 'm':
+			t = t.Add(time.Duration(n) * time.Minute)
+		case 'h':
+			t = t.Add(time.Duration(n) * time.Hour)
+		case 'd':
+			t = t.AddDate(0, 0, n)
+		case 'w':
+			t = t.AddDate(0, 0, 7*n)
+		default:
+			return time.Time{}, false
+		}
+		offsets = offsets[digits+1:]
+	}
+
+	return t, true
+}
@@ -0,0 +1,169 @@
+package hapi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValueParseTimeAt(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		value Value
+		now   time.Time
+		want  time.Time
+	}{
+		{"2024-01-31T12:00:00Z", now, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{"2024-01-31T12:00:00.5+02:00", now, time.Date(2024, 1, 31, 10, 0, 0, 5e8, time.UTC)},
+		{"2024-01-31", now, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2024-01-31", now.In(paris), time.Date(2024, 1, 31, 0, 0, 0, 0, paris)},
+		{"1706702400", now, time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)},
+		{"1706702400123", now, time.Date(2024, 1, 31, 12, 0, 0, 123e6, time.UTC)},
+		{"now", now, now},
+		{"today", now, time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"yesterday", now, time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"tomorrow", now, time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"now-7d", now, time.Date(2024, 3, 8, 14, 30, 0, 0, time.UTC)},
+		{"now+90m", now, time.Date(2024, 3, 15, 16, 0, 0, 0, time.UTC)},
+		{"now 1h", now, time.Date(2024, 3, 15, 15, 30, 0, 0, time.UTC)},
+		{"today+1d-2h", now, time.Date(2024, 3, 15, 22, 0, 0, 0, time.UTC)},
+		{"now-2w+30s", now, time.Date(2024, 3, 1, 14, 30, 30, 0, time.UTC)},
+		// Days are calendar days: the time of day is kept across the DST change of March 31.
+		{"now+1d", time.Date(2024, 3, 30, 12, 0, 0, 0, paris), time.Date(2024, 3, 31, 12, 0, 0, 0, paris)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.value), func(t *testing.T) {
+			got, err := tt.value.ParseTimeAt(tt.now)
+			if err != nil {
+				t.Fatalf("ParseTimeAt() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseTimeAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueParseTimeAtErrors(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	for _, v := range []Value{"", "abc", "2024-13-01", "now-", "now-7", "now-d", "now-7y", "today+1d+", "now-7dx3h", "now-7d*3h", "later"} {
+		if _, err := v.ParseTimeAt(now); err == nil {
+			t.Errorf("ParseTimeAt(%q) expected error, got nil", v)
+		}
+	}
+}
+
+func TestValueTime(t *testing.T) {
+	if got := Value("2024-01-31T12:00:00Z").Time(); !got.Equal(time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Time() = %v, want 2024-01-31T12:00:00Z", got)
+	}
+	if got := Value("abc").Time(); !got.IsZero() {
+		t.Errorf("Time() = %v, want zero time", got)
+	}
+}
+
+// Relative time values of typed fields are resolved once, against the
+// configured clock, so the parsed Result is reproducible.
+func TestParse_TimeFieldsNormalized(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	opts := *NewOptions(
+		WithClock(func() time.Time { return now }),
+		WithFieldTypes(map[string]FieldType{"created_at": FieldTypeTime}),
+	)
+
+	r, err := ParseStrict("http://x/users?created_at[ge]=now-7d&created_at[lt]=2024-03-15&created_at[bt]=yesterday,today&since=now-7d", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Values{
+		{"2024-03-08T14:30:00Z"},
+		{"2024-03-15T00:00:00Z"},
+		{"2024-03-14T00:00:00Z", "2024-03-15T00:00:00Z"},
+		// Untyped fields keep their raw value.
+		{"now-7d"},
+	}
+	got := make([]Values, len(r.Filters))
+	for i, filter := range r.Filters {
+		got[i] = filter.Values
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter values = %v, want %v", got, want)
+	}
+
+	if _, err := ParseStrict("http://x/users?created_at[bt]=today,yesterday", opts); err == nil {
+		t.Error("ParseStrict() expected error for reversed relative range, got nil")
+	}
+	if _, err := ParseStrict("http://x/users?created_at[ge]=now-7dx3h", opts); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ParseStrict() error = %v, want %v for an invalid offset separator", err, ErrInvalidValue)
+	}
+}
+
+// Relative times of untyped fields are evaluated against the clock of the
+// parse, exposed as Result.Now, by Bind and the At conversions.
+func TestParse_TimeClock(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	opts := *NewOptions(WithClock(func() time.Time { return now }))
+
+	r, err := ParseStrict("http://x/users?since=now-7d&until[in]=today,tomorrow", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Now.Equal(now) {
+		t.Errorf("Now = %v, want %v", r.Now, now)
+	}
+
+	var query struct {
+		Since *time.Time  `hapi:"since"`
+		Until []time.Time `hapi:"until,in"`
+	}
+	if err := Bind(r, &query); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 8, 14, 30, 0, 0, time.UTC); query.Since == nil || !query.Since.Equal(want) {
+		t.Errorf("Bind() Since = %v, want %v", query.Since, want)
+	}
+	if want := time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC); len(query.Until) != 2 || !query.Until[1].Equal(want) {
+		t.Errorf("Bind() Until = %v, want [..., %v]", query.Until, want)
+	}
+
+	since := r.Filters.GetFirstFromField("since").Values
+	if got := since.First().TimeAt(r.Now); !got.Equal(*query.Since) {
+		t.Errorf("TimeAt() = %v, want %v", got, query.Since)
+	}
+	if got, err := since.TimesAt(r.Now); err != nil || !got[0].Equal(*query.Since) {
+		t.Errorf("TimesAt() = %v, %v, want [%v]", got, err, query.Since)
+	}
+	if got, err := AsAt[time.Time](since.First(), r.Now); err != nil || !got.Equal(*query.Since) {
+		t.Errorf("AsAt() = %v, %v, want %v", got, err, query.Since)
+	}
+}
+
+// Without a clock, Result.Now stays zero so that Results compare equal.
+func TestParse_TimeWithoutClock(t *testing.T) {
+	r, err := ParseStrict("http://x/users?since=now-7d", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Now.IsZero() {
+		t.Errorf("Now = %v, want the zero time without a clock", r.Now)
+	}
+
+	var query struct {
+		Since time.Time `hapi:"since"`
+	}
+	if err := Bind(r, &query); err != nil {
+		t.Fatal(err)
+	}
+	if ago := time.Since(query.Since); ago < 7*24*time.Hour-time.Minute || ago > 7*24*time.Hour+time.Minute {
+		t.Errorf("Bind() Since = %v, want about 7 days ago", query.Since)
+	}
+}
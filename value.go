@@ -1,6 +1,11 @@
 package hapi
 
-import "strconv"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
 
 // Values represents a collection of Value items.
 type Values []Value
@@ -27,6 +32,42 @@ func (v Values) ToString() []string {
 	return res
 }
 
+// Ints converts all values to integers.
+// It fails with a *ValueError identifying the first value that does not convert.
+func (v Values) Ints() ([]int, error) {
+	return AsSlice[int](v)
+}
+
+// Int64s converts all values to int64.
+// It fails with a *ValueError identifying the first value that does not convert.
+func (v Values) Int64s() ([]int64, error) {
+	return AsSlice[int64](v)
+}
+
+// Float64s converts all values to float64.
+// It fails with a *ValueError identifying the first value that does not convert.
+func (v Values) Float64s() ([]float64, error) {
+	return AsSlice[float64](v)
+}
+
+// Bools converts all values to booleans.
+// It fails with a *ValueError identifying the first value that does not convert.
+func (v Values) Bools() ([]bool, error) {
+	return AsSlice[bool](v)
+}
+
+// Times converts all values to times, see Value.ParseTime.
+// It fails with a *ValueError identifying the first value that does not convert.
+func (v Values) Times() ([]time.Time, error) {
+	return AsSlice[time.Time](v)
+}
+
+// TimesAt converts all values to times, evaluating relative expressions
+// against now, see Value.ParseTimeAt.
+func (v Values) TimesAt(now time.Time) ([]time.Time, error) {
+	return AsSliceAt[time.Time](v, now)
+}
+
 // Value represents a single string value that can be converted to various types.
 type Value string
 
@@ -35,6 +76,42 @@ func (v Value) String() string {
 	return string(v)
 }
 
+// ParseInt converts the value to an integer.
+func (v Value) ParseInt() (int, error) {
+	n, err := strconv.Atoi(string(v))
+	if err != nil {
+		return 0, conversionError("int", v, err)
+	}
+	return n, nil
+}
+
+// ParseInt64 converts the value to an int64.
+func (v Value) ParseInt64() (int64, error) {
+	n, err := strconv.ParseInt(string(v), 10, 64)
+	if err != nil {
+		return 0, conversionError("int64", v, err)
+	}
+	return n, nil
+}
+
+// ParseFloat64 converts the value to a float64.
+func (v Value) ParseFloat64() (float64, error) {
+	f, err := strconv.ParseFloat(string(v), 64)
+	if err != nil {
+		return 0, conversionError("float64", v, err)
+	}
+	return f, nil
+}
+
+// ParseBool converts the value to a boolean, see strconv.ParseBool for the accepted values.
+func (v Value) ParseBool() (bool, error) {
+	b, err := strconv.ParseBool(string(v))
+	if err != nil {
+		return false, conversionError("bool", v, err)
+	}
+	return b, nil
+}
+
 // Int converts the value to an integer, returning 0 if conversion fails.
 func (v Value) Int() int {
 	if v == "" {
@@ -83,3 +160,13 @@ func (v Value) Bool() bool {
 	}
 	return res
 }
+
+// conversionError reports that v is not a valid value of the named type.
+// Errors from strconv are reduced to their cause, e.g. strconv.ErrSyntax.
+func conversionError(typ string, v Value, err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	return fmt.Errorf("invalid %s value %q: %w", typ, v, err)
+}
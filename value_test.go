@@ -1,7 +1,9 @@
 package hapi
 
 import (
+	"errors"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -166,3 +168,28 @@ func TestValueBool(t *testing.T) {
 		})
 	}
 }
+
+// The error-returning conversions tell an invalid value apart from a zero value.
+func TestValueParseConversions(t *testing.T) {
+	if n, err := Value("0").ParseInt(); n != 0 || err != nil {
+		t.Errorf("ParseInt(0) = %v, %v, want 0, nil", n, err)
+	}
+	if _, err := Value("oops").ParseInt(); !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("ParseInt(oops) error = %v, want strconv.ErrSyntax", err)
+	}
+	if _, err := Value("99999999999999999999").ParseInt64(); !errors.Is(err, strconv.ErrRange) {
+		t.Errorf("ParseInt64(overflow) error = %v, want strconv.ErrRange", err)
+	}
+	if f, err := Value("4.5").ParseFloat64(); f != 4.5 || err != nil {
+		t.Errorf("ParseFloat64(4.5) = %v, %v, want 4.5, nil", f, err)
+	}
+	if _, err := Value("").ParseFloat64(); err == nil {
+		t.Error("ParseFloat64() expected error for empty value, got nil")
+	}
+	if b, err := Value("t").ParseBool(); !b || err != nil {
+		t.Errorf("ParseBool(t) = %v, %v, want true, nil", b, err)
+	}
+	if _, err := Value("yes").ParseBool(); err == nil {
+		t.Error("ParseBool(yes) expected error, got nil")
+	}
+}